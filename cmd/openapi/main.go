@@ -0,0 +1,27 @@
+// Command openapi writes the OpenAPI document of the API, run it with go generate ./internal/services
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"remi/internal/services"
+	"remi/pkg/config"
+)
+
+func main() {
+	out := flag.String("out", "api/openapi.json", "path of the generated document")
+	flag.Parse()
+
+	// routes are declared without touching the database
+	remiService := services.NewRemiService(nil, &config.Config{})
+	data, err := remiService.OpenAPI()
+	if err != nil {
+		log.Fatalf("remiService.OpenAPI: %v", err)
+	}
+
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		log.Fatalf("os.WriteFile: %v", err)
+	}
+}
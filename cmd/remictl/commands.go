@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"remi/pkg/client"
+	"remi/up"
+
+	"golang.org/x/term"
+)
+
+type command struct {
+	cfg    *config
+	client *client.Client
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func (c *command) api() *client.Client {
+	if c.client == nil {
+		c.client = client.New(c.cfg.Server, client.WithTokens(c.cfg.Token, c.cfg.RefreshToken))
+	}
+	return c.client
+}
+
+func (c *command) saveSession(path string) error {
+	if c.client == nil {
+		return nil
+	}
+
+	c.cfg.Token, c.cfg.RefreshToken = c.client.Tokens()
+	return c.cfg.save(path)
+}
+
+func (c *command) requireLogin() error {
+	if c.cfg.Token == "" && c.cfg.RefreshToken == "" {
+		return errors.New("not logged in, run remictl login first")
+	}
+	return nil
+}
+
+func (c *command) login(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	server := fs.String("server", c.cfg.Server, "url of the Remi server")
+	username := fs.String("username", c.cfg.Username, "username")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if *username == "" {
+		return errors.New("--username is required")
+	}
+	password, err := c.readPassword()
+	if err != nil {
+		return err
+	}
+
+	c.cfg.Server = strings.TrimSuffix(*server, "/")
+	c.client = nil
+	resp, err := c.api().Login(ctx, &up.LoginRequest{
+		Username: *username,
+		Password: password,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.cfg.Username = resp.Username
+	fmt.Fprintf(c.stdout, "logged in as %s\n", resp.Name)
+	return nil
+}
+
+func (c *command) readPassword() (string, error) {
+	if password := os.Getenv("REMI_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	fmt.Fprint(c.stdout, "password: ")
+	// don't echo the password typed in a terminal, piped input is read as is
+	if f, ok := c.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		password, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(c.stdout)
+		if err != nil {
+			return "", fmt.Errorf("can't read password: %w", err)
+		}
+		return string(password), nil
+	}
+
+	line, err := bufio.NewReader(c.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("can't read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *command) logout(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("logout", flag.ContinueOnError)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := c.requireLogin(); err != nil {
+		return err
+	}
+
+	if _, err := c.api().Logout(ctx, &up.LogoutRequest{}); err != nil {
+		return err
+	}
+
+	c.cfg.Username = ""
+	fmt.Fprintln(c.stdout, "logged out")
+	return nil
+}
+
+func (c *command) share(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("share", flag.ContinueOnError)
+	name := fs.String("name", "", "name of the movie, fetched from the video when empty")
+	description := fs.String("description", "", "description of the movie")
+	tags := fs.String("tags", "", "comma separated tags of the movie")
+	output := fs.String("o", outputTable, "output format: table or json")
+	links, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(links) != 1 {
+		return errors.New("usage: remictl share <link> [--name NAME] [--description TEXT] [--tags TAG,...]")
+	}
+	if err := validOutput(*output); err != nil {
+		return err
+	}
+	if err := c.requireLogin(); err != nil {
+		return err
+	}
+
+	resp, err := c.api().CreateMovie(ctx, &up.CreateMovieRequest{
+		Name:        *name,
+		Description: *description,
+		Link:        links[0],
+		Tags:        splitTags(*tags),
+	})
+	if err != nil {
+		return err
+	}
+
+	if *output == outputJSON {
+		return printJSON(c.stdout, resp)
+	}
+	fmt.Fprintf(c.stdout, "shared movie %s\n", resp.ID)
+	return nil
+}
+
+type listFlags struct {
+	limit  *int
+	offset *int
+	cursor *string
+	output *string
+}
+
+func newListFlags(fs *flag.FlagSet) *listFlags {
+	return &listFlags{
+		limit:  fs.Int("limit", 10, "number of movies per page"),
+		offset: fs.Int("offset", 0, "number of movies to skip, ignored when --cursor is set"),
+		cursor: fs.String("cursor", "", "cursor of the page, printed after the previous page"),
+		output: fs.String("o", outputTable, "output format: table or json"),
+	}
+}
+
+func (c *command) feed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("feed", flag.ContinueOnError)
+	lf := newListFlags(fs)
+	tags := fs.String("tags", "", "comma separated tags, only lists the movies with any of them")
+	allTags := fs.Bool("all-tags", false, "only list the movies with all of --tags")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := validOutput(*lf.output); err != nil {
+		return err
+	}
+
+	tagMode := up.TagModeAny
+	if *allTags {
+		tagMode = up.TagModeAll
+	}
+	resp, err := c.api().ListMovies(ctx, &up.ListMoviesRequest{
+		Offset:  lf.offset,
+		Limit:   lf.limit,
+		Cursor:  *lf.cursor,
+		Tags:    splitTags(*tags),
+		TagMode: tagMode,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *lf.output == outputJSON {
+		return printJSON(c.stdout, resp)
+	}
+	return printMovies(c.stdout, resp.Movies, resp.NextCursor)
+}
+
+func (c *command) mine(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("mine", flag.ContinueOnError)
+	lf := newListFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := validOutput(*lf.output); err != nil {
+		return err
+	}
+	if err := c.requireLogin(); err != nil {
+		return err
+	}
+
+	resp, err := c.api().ListMoviesByUser(ctx, &up.ListMoviesByUserRequest{
+		Offset: lf.offset,
+		Limit:  lf.limit,
+		Cursor: *lf.cursor,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *lf.output == outputJSON {
+		return printJSON(c.stdout, resp)
+	}
+	return printMovies(c.stdout, resp.Movies, resp.NextCursor)
+}
+
+// splitTags splits a comma separated list of tags, the server normalizes them
+func splitTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// parseArgs parses the flags of fs wherever they are placed and returns the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
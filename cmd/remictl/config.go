@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// config is saved between runs, it holds the session created by login
+type config struct {
+	Server       string `json:"server"`
+	Username     string `json:"username,omitempty"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// configPath returns $REMICTL_CONFIG, or remictl/config.json in the user config directory
+func configPath() (string, error) {
+	if path := os.Getenv("REMICTL_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("os.UserConfigDir: %w", err)
+	}
+	return filepath.Join(dir, "remictl", "config.json"), nil
+}
+
+func loadConfig(path string) (*config, error) {
+	cfg := &config{
+		Server: defaultServer,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("can't parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// save writes the config readable by the owner only since it contains the tokens
+func (c *config) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Command remictl shares and browses movies from the terminal.
+//
+//	remictl login --server http://localhost:8080 --username alice
+//	remictl share https://www.youtube.com/watch?v=id --name "Funny cats"
+//	remictl feed --limit 20
+//	remictl mine -o json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `remictl shares and browses movies from the terminal.
+
+Usage:
+  remictl login [--server URL] [--username NAME]   log in, the password is read from stdin or $REMI_PASSWORD
+  remictl logout                                 revoke the session
+  remictl share <link> [--name NAME] [--description TEXT] [--tags TAG,...]
+  remictl feed [--limit N] [--cursor CURSOR] [--tags TAG,...] [--all-tags]
+                                                 list the movies shared by everyone
+  remictl mine [--limit N] [--cursor CURSOR]     list the movies I shared
+
+Listing commands accept -o table|json.
+The session is saved in $REMICTL_CONFIG, by default remictl/config.json in the user config directory.
+`
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "remictl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stdout, usage)
+		return nil
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	cmd := &command{
+		cfg:    cfg,
+		stdin:  stdin,
+		stdout: stdout,
+	}
+
+	switch args[0] {
+	case "login":
+		err = cmd.login(ctx, args[1:])
+	case "logout":
+		err = cmd.logout(ctx, args[1:])
+	case "share":
+		err = cmd.share(ctx, args[1:])
+	case "feed":
+		err = cmd.feed(ctx, args[1:])
+	case "mine":
+		err = cmd.mine(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, run remictl help", args[0])
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
+	// tokens may have been refreshed by any command
+	if saveErr := cmd.saveSession(path); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return err
+}
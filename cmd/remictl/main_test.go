@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"remi/up"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/login":
+			var req up.LoginRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, "secret", req.Password)
+			json.NewEncoder(w).Encode(up.LoginResponse{Username: req.Username, Name: "Alice", Token: "token", RefreshToken: "refresh-token"})
+		case "/api/v2/movies":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			if r.Method == http.MethodPost {
+				var req up.CreateMovieRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				assert.Equal(t, "https://www.youtube.com/watch?v=id", req.Link)
+				assert.Equal(t, "Funny cats", req.Name)
+				json.NewEncoder(w).Encode(up.CreateMovieResponse{ID: "movie-id"})
+				return
+			}
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+			json.NewEncoder(w).Encode(up.ListMoviesResponse{
+				Movies:     []*up.Movie{{ID: "movie-id", Name: "Funny cats", SharedBy: "Alice"}},
+				NextCursor: "next",
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("REMICTL_CONFIG", path)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	err := run(ctx, []string{"login", "--server", server.URL, "--username", "alice"}, strings.NewReader("secret\n"), &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "logged in as Alice")
+
+	cfg, err := loadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, &config{Server: server.URL, Username: "alice", Token: "token", RefreshToken: "refresh-token"}, cfg)
+
+	out.Reset()
+	err = run(ctx, []string{"share", "https://www.youtube.com/watch?v=id", "--name", "Funny cats"}, nil, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "shared movie movie-id\n", out.String())
+
+	out.Reset()
+	err = run(ctx, []string{"feed", "--limit", "2"}, nil, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Funny cats")
+	assert.Contains(t, out.String(), "--cursor next")
+
+	out.Reset()
+	err = run(ctx, []string{"feed", "--limit", "2", "-o", "json"}, nil, &out)
+	require.NoError(t, err)
+	var resp up.ListMoviesResponse
+	require.NoError(t, json.Unmarshal(out.Bytes(), &resp))
+	assert.Equal(t, "movie-id", resp.Movies[0].ID)
+}
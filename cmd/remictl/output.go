@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"remi/up"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func validOutput(output string) error {
+	if output != outputTable && output != outputJSON {
+		return fmt.Errorf("output must be %s or %s", outputTable, outputJSON)
+	}
+	return nil
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printMovies prints the page as a table, followed by the command to get the next page
+func printMovies(w io.Writer, movies []*up.Movie, nextCursor string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tSHARED BY\tSHARED AT\tLIKES\tDISLIKES\tLINK")
+	for _, m := range movies {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			m.ID, truncate(m.Name, 40), m.SharedBy, m.SharedAt.Local().Format(time.RFC822), m.Likes, m.Dislikes, m.Link)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if nextCursor != "" {
+		fmt.Fprintf(w, "\nnext page: --cursor %s\n", nextCursor)
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
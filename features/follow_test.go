@@ -0,0 +1,71 @@
+package features
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"remi/pkg/client"
+	"remi/pkg/golibs/idutil"
+	"remi/up"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowService_ListFeed_Success(t *testing.T) {
+	follower := newLoggedInClient(t)
+	followee := newLoggedInClient(t)
+	stranger := newLoggedInClient(t)
+	ctx := context.Background()
+
+	followeeProfile, err := followee.GetProfile(ctx)
+	require.NoError(t, err)
+	followerProfile, err := follower.GetProfile(ctx)
+	require.NoError(t, err)
+
+	_, err = follower.FollowUser(ctx, &up.FollowUserRequest{UserID: followeeProfile.ID})
+	require.NoError(t, err)
+
+	createMovieResp, err := followee.CreateMovie(ctx, &up.CreateMovieRequest{
+		Name: "movie-" + idutil.NewID(),
+		Link: "https://www.youtube.com/watch?v=" + idutil.NewID(),
+	})
+	require.NoError(t, err)
+	_, err = stranger.CreateMovie(ctx, &up.CreateMovieRequest{
+		Name: "movie-" + idutil.NewID(),
+		Link: "https://www.youtube.com/watch?v=" + idutil.NewID(),
+	})
+	require.NoError(t, err)
+
+	feedResp, err := follower.ListFeed(ctx, &up.ListFeedRequest{})
+	require.NoError(t, err)
+	require.Len(t, feedResp.Movies, 1)
+	assert.Equal(t, createMovieResp.ID, feedResp.Movies[0].ID)
+
+	followersResp, err := follower.ListFollowers(ctx, &up.ListFollowersRequest{UserID: followeeProfile.ID})
+	require.NoError(t, err)
+	require.Len(t, followersResp.Users, 1)
+	assert.Equal(t, followerProfile.ID, followersResp.Users[0].ID)
+
+	_, err = follower.UnfollowUser(ctx, &up.UnfollowUserRequest{UserID: followeeProfile.ID})
+	require.NoError(t, err)
+
+	feedResp, err = follower.ListFeed(ctx, &up.ListFeedRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, feedResp.Movies)
+}
+
+func TestFollowService_FollowUser_Self(t *testing.T) {
+	c := newLoggedInClient(t)
+	ctx := context.Background()
+
+	profile, err := c.GetProfile(ctx)
+	require.NoError(t, err)
+
+	_, err = c.FollowUser(ctx, &up.FollowUserRequest{UserID: profile.ID})
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
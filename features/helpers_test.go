@@ -0,0 +1,41 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"remi/pkg/client"
+	"remi/pkg/golibs/idutil"
+	"remi/up"
+
+	"github.com/stretchr/testify/require"
+)
+
+const serverURL = "http://localhost:8080"
+
+func newRegisterRequest() *up.RegisterRequest {
+	return &up.RegisterRequest{
+		Name:     fmt.Sprintf("name-" + idutil.NewID()),
+		Username: fmt.Sprintf("user-" + idutil.NewID()),
+		Password: "password-1",
+	}
+}
+
+// newLoggedInClient registers a new user and returns a client logged in as the user
+func newLoggedInClient(t *testing.T) *client.Client {
+	c := client.New(serverURL)
+	ctx := context.Background()
+
+	registerReq := newRegisterRequest()
+	_, err := c.Register(ctx, registerReq)
+	require.NoError(t, err)
+
+	_, err = c.Login(ctx, &up.LoginRequest{
+		Username: registerReq.Username,
+		Password: registerReq.Password,
+	})
+	require.NoError(t, err)
+
+	return c
+}
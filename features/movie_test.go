@@ -1,245 +1,49 @@
 package features
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"log"
+	"context"
 	"net/http"
+	"testing"
+
+	"remi/pkg/client"
 	"remi/pkg/golibs/idutil"
 	"remi/up"
-	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMovieService_Create_Success(t *testing.T) {
-	registerReq := &up.RegisterRequest{
-		Name:     fmt.Sprintf("name-" + idutil.NewID()),
-		Username: fmt.Sprintf("user-" + idutil.NewID()),
-		Password: "password",
-	}
-	registerReqMarshalled, err := json.Marshal(registerReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't marshal RegisterRequest: %w", err))
-	}
-
-	registerRespMarshalled, err := http.Post("http://localhost:8080/api/v1/register", "application/json", bytes.NewBuffer(registerReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("error when call api register: %w", err))
-	}
-	assert.Equal(t, registerRespMarshalled.StatusCode, http.StatusOK)
-
-	loginReq := &up.LoginRequest{
-		Username: registerReq.Username,
-		Password: registerReq.Password,
-	}
-	loginReqMarshalled, err := json.Marshal(loginReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't marshal loginRequest: %w", err))
-	}
-
-	loginRespMarshalled, err := http.Post("http://localhost:8080/api/v1/login", "application/json", bytes.NewBuffer(loginReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("error when call api login: %w", err))
-	}
-	assert.Equal(t, loginRespMarshalled.StatusCode, http.StatusOK)
-
-	defer loginRespMarshalled.Body.Close()
+	c := newLoggedInClient(t)
 
-	loginRespMarshalledBody, err := ioutil.ReadAll(loginRespMarshalled.Body)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't ioutil.ReadAll loginRespMarshalled.Body %w", err))
-	}
-
-	var loginResp up.LoginResponse
-	if err := json.Unmarshal(loginRespMarshalledBody, &loginResp); err != nil {
-		log.Fatal(fmt.Errorf("can't unmarshal loginRespMarshalledBody %w", err))
-	}
-
-	assert.Equal(t, registerReq.Username, loginResp.Username)
-	assert.Equal(t, registerReq.Name, loginResp.Name)
-	assert.NotEmpty(t, loginResp.Token)
-
-	createMovieReq := &up.CreateMovieRequest{
+	createMovieResp, err := c.CreateMovie(context.Background(), &up.CreateMovieRequest{
 		Name:        "movie-" + idutil.NewID(),
 		Description: "description-" + idutil.NewID(),
 		Link:        "https://www.youtube.com/watch?v=" + idutil.NewID(),
-	}
-
-	createMovieReqMarshalled, err := json.Marshal(createMovieReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't marshal createMovieReq: %w", err))
-	}
-
-	client := &http.Client{}
-	createMovieHttpReq, err := http.NewRequest("POST", "http://localhost:8080/api/v1/createMovie", bytes.NewBuffer(createMovieReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("createMovie - http.NewRequest: %w", err))
-	}
-
-	createMovieHttpReq.Header.Set("Content-Type", "application/json")
-	createMovieHttpReq.Header.Set("authorization", loginResp.Token)
-
-	createMovieRespMarshalled, err := client.Do(createMovieHttpReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("createMovie - client.Do: %w", err))
-	}
-
-	assert.Equal(t, http.StatusOK, createMovieRespMarshalled.StatusCode)
+	})
+	require.NoError(t, err)
 
-	defer loginRespMarshalled.Body.Close()
-
-	createMovieRespMarshalledBody, err := ioutil.ReadAll(createMovieRespMarshalled.Body)
-	if err != nil {
-		log.Fatal(fmt.Errorf("createMovie - ioutil.ReadAll %w", err))
-	}
-
-	var createMovieResp up.CreateMovieResponse
-	if err := json.Unmarshal(createMovieRespMarshalledBody, &createMovieResp); err != nil {
-		log.Fatal(fmt.Errorf("can't unmarshal createMovieRespMarshalledBody %w", err))
-	}
-
-	assert.Equal(t, http.StatusOK, createMovieRespMarshalled.StatusCode)
 	assert.NotEmpty(t, createMovieResp.ID)
 }
 
 func TestMovieService_Create_Error(t *testing.T) {
-	registerReq := &up.RegisterRequest{
-		Name:     fmt.Sprintf("name-" + idutil.NewID()),
-		Username: fmt.Sprintf("user-" + idutil.NewID()),
-		Password: "password",
-	}
-	registerReqMarshalled, err := json.Marshal(registerReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't marshal RegisterRequest: %w", err))
-	}
-
-	registerRespMarshalled, err := http.Post("http://localhost:8080/api/v1/register", "application/json", bytes.NewBuffer(registerReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("error when call api register: %w", err))
-	}
-	assert.Equal(t, registerRespMarshalled.StatusCode, http.StatusOK)
-
-	loginReq := &up.LoginRequest{
-		Username: registerReq.Username,
-		Password: registerReq.Password,
-	}
-	loginReqMarshalled, err := json.Marshal(loginReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't marshal loginRequest: %w", err))
-	}
+	c := newLoggedInClient(t)
 
-	loginRespMarshalled, err := http.Post("http://localhost:8080/api/v1/login", "application/json", bytes.NewBuffer(loginReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("error when call api login: %w", err))
-	}
-	assert.Equal(t, loginRespMarshalled.StatusCode, http.StatusOK)
-
-	defer loginRespMarshalled.Body.Close()
-
-	loginRespMarshalledBody, err := ioutil.ReadAll(loginRespMarshalled.Body)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't ioutil.ReadAll loginRespMarshalled.Body %w", err))
-	}
-
-	var loginResp up.LoginResponse
-	if err := json.Unmarshal(loginRespMarshalledBody, &loginResp); err != nil {
-		log.Fatal(fmt.Errorf("can't unmarshal loginRespMarshalledBody %w", err))
-	}
-
-	assert.Equal(t, registerReq.Username, loginResp.Username)
-	assert.Equal(t, registerReq.Name, loginResp.Name)
-	assert.NotEmpty(t, loginResp.Token)
-
-	createMovieReq := &up.CreateMovieRequest{
+	_, err := c.CreateMovie(context.Background(), &up.CreateMovieRequest{
 		Name:        "movie-" + idutil.NewID(),
 		Description: "description-" + idutil.NewID(),
 		Link:        "link-" + idutil.NewID(),
-	}
-
-	createMovieReqMarshalled, err := json.Marshal(createMovieReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't marshal createMovieReq: %w", err))
-	}
-
-	client := &http.Client{}
-	createMovieHttpReq, err := http.NewRequest("POST", "http://localhost:8080/api/v1/createMovie", bytes.NewBuffer(createMovieReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("createMovie - http.NewRequest: %w", err))
-	}
-
-	createMovieHttpReq.Header.Set("Content-Type", "application/json")
-	createMovieHttpReq.Header.Set("authorization", loginResp.Token)
+	})
 
-	createMovieRespMarshalled, err := client.Do(createMovieHttpReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("createMovie - client.Do: %w", err))
-	}
-	defer loginRespMarshalled.Body.Close()
-
-	createMovieRespMarshalledBody, err := ioutil.ReadAll(createMovieRespMarshalled.Body)
-	if err != nil {
-		log.Fatal(fmt.Errorf("createMovie - ioutil.ReadAll %w", err))
-	}
-
-	var errorResp up.ErrorResponse
-	if err := json.Unmarshal(createMovieRespMarshalledBody, &errorResp); err != nil {
-		log.Fatal(fmt.Errorf("can't unmarshal createMovieRespMarshalledBody %w", err))
-	}
-
-	assert.Equal(t, http.StatusBadRequest, createMovieRespMarshalled.StatusCode)
-	assert.NotEmpty(t, errorResp.Error)
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.NotEmpty(t, apiErr.Message)
 }
 
 func TestMovieService_ListMovies_Success(t *testing.T) {
-	registerReq := &up.RegisterRequest{
-		Name:     fmt.Sprintf("name-" + idutil.NewID()),
-		Username: fmt.Sprintf("user-" + idutil.NewID()),
-		Password: "password",
-	}
-	registerReqMarshalled, err := json.Marshal(registerReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't marshal RegisterRequest: %w", err))
-	}
-
-	registerRespMarshalled, err := http.Post("http://localhost:8080/api/v1/register", "application/json", bytes.NewBuffer(registerReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("error when call api register: %w", err))
-	}
-	assert.Equal(t, registerRespMarshalled.StatusCode, http.StatusOK)
-
-	loginReq := &up.LoginRequest{
-		Username: registerReq.Username,
-		Password: registerReq.Password,
-	}
-	loginReqMarshalled, err := json.Marshal(loginReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't marshal loginRequest: %w", err))
-	}
-
-	loginRespMarshalled, err := http.Post("http://localhost:8080/api/v1/login", "application/json", bytes.NewBuffer(loginReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("error when call api login: %w", err))
-	}
-	assert.Equal(t, loginRespMarshalled.StatusCode, http.StatusOK)
-
-	defer loginRespMarshalled.Body.Close()
-
-	loginRespMarshalledBody, err := ioutil.ReadAll(loginRespMarshalled.Body)
-	if err != nil {
-		log.Fatal(fmt.Errorf("can't ioutil.ReadAll loginRespMarshalled.Body %w", err))
-	}
-
-	var loginResp up.LoginResponse
-	if err := json.Unmarshal(loginRespMarshalledBody, &loginResp); err != nil {
-		log.Fatal(fmt.Errorf("can't unmarshal loginRespMarshalledBody %w", err))
-	}
-
-	assert.Equal(t, registerReq.Username, loginResp.Username)
-	assert.Equal(t, registerReq.Name, loginResp.Name)
-	assert.NotEmpty(t, loginResp.Token)
+	c := newLoggedInClient(t)
+	ctx := context.Background()
 
 	createMovieRepMap := make(map[string]*up.CreateMovieRequest)
 	for i := 0; i < 5; i++ {
@@ -249,40 +53,8 @@ func TestMovieService_ListMovies_Success(t *testing.T) {
 			Link:        "https://www.youtube.com/watch?v=" + idutil.NewID(),
 		}
 
-		createMovieReqMarshalled, err := json.Marshal(createMovieReq)
-		if err != nil {
-			log.Fatal(fmt.Errorf("can't marshal createMovieReq: %w", err))
-		}
-
-		client := &http.Client{}
-		createMovieHttpReq, err := http.NewRequest("POST", "http://localhost:8080/api/v1/createMovie", bytes.NewBuffer(createMovieReqMarshalled))
-		if err != nil {
-			log.Fatal(fmt.Errorf("createMovie - http.NewRequest: %w", err))
-		}
-
-		createMovieHttpReq.Header.Set("Content-Type", "application/json")
-		createMovieHttpReq.Header.Set("authorization", loginResp.Token)
-
-		createMovieRespMarshalled, err := client.Do(createMovieHttpReq)
-		if err != nil {
-			log.Fatal(fmt.Errorf("createMovie - client.Do: %w", err))
-		}
-
-		assert.Equal(t, http.StatusOK, createMovieRespMarshalled.StatusCode)
-
-		defer loginRespMarshalled.Body.Close()
-
-		createMovieRespMarshalledBody, err := ioutil.ReadAll(createMovieRespMarshalled.Body)
-		if err != nil {
-			log.Fatal(fmt.Errorf("createMovie - ioutil.ReadAll %w", err))
-		}
-
-		var createMovieResp up.CreateMovieResponse
-		if err := json.Unmarshal(createMovieRespMarshalledBody, &createMovieResp); err != nil {
-			log.Fatal(fmt.Errorf("can't unmarshal createMovieRespMarshalledBody %w", err))
-		}
-
-		assert.Equal(t, http.StatusOK, createMovieRespMarshalled.StatusCode)
+		createMovieResp, err := c.CreateMovie(ctx, createMovieReq)
+		require.NoError(t, err)
 		assert.NotEmpty(t, createMovieResp.ID)
 
 		createMovieRepMap[createMovieResp.ID] = createMovieReq
@@ -290,47 +62,47 @@ func TestMovieService_ListMovies_Success(t *testing.T) {
 
 	offset := 0
 	limit := 5
-	listMoviesReq := &up.ListMoviesRequest{
+	listMoviesResp, err := c.ListMovies(ctx, &up.ListMoviesRequest{
 		Offset: &offset,
 		Limit:  &limit,
-	}
-
-	listMoviesReqMarshalled, err := json.Marshal(listMoviesReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("listMovies - json.Marshal: %w", err))
-	}
-
-	client := &http.Client{}
-	listMoviesHttpReq, err := http.NewRequest("POST", "http://localhost:8080/api/v1/listMovies", bytes.NewBuffer(listMoviesReqMarshalled))
-	if err != nil {
-		log.Fatal(fmt.Errorf("listMovies - http.NewRequest: %w", err))
-	}
-
-	listMoviesHttpReq.Header.Set("Content-Type", "application/json")
-	listMoviesHttpReq.Header.Set("authorization", loginResp.Token)
-
-	listMoviesRespMarshalled, err := client.Do(listMoviesHttpReq)
-	if err != nil {
-		log.Fatal(fmt.Errorf("listMovies - client.Do: %w", err))
-	}
-
-	assert.Equal(t, http.StatusOK, listMoviesRespMarshalled.StatusCode)
-
-	defer listMoviesRespMarshalled.Body.Close()
-
-	listMoviesRespMarshalledBody, err := ioutil.ReadAll(listMoviesRespMarshalled.Body)
-	if err != nil {
-		log.Fatal(fmt.Errorf("listMovies - ioutil.ReadAll %w", err))
-	}
-
-	var listMoviesResp up.ListMoviesResponse
-	if err := json.Unmarshal(listMoviesRespMarshalledBody, &listMoviesResp); err != nil {
-		log.Fatal(fmt.Errorf("can't unmarshal createMovieRespMarshalledBody %w", err))
-	}
-
-	assert.Equal(t, http.StatusOK, listMoviesRespMarshalled.StatusCode)
+	})
+	require.NoError(t, err)
 
 	for _, movie := range listMoviesResp.Movies {
 		assert.NotNil(t, createMovieRepMap[movie.ID])
 	}
 }
+
+func TestMovieService_ListMoviesByUsername_Success(t *testing.T) {
+	c := client.New(serverURL)
+	ctx := context.Background()
+
+	registerReq := newRegisterRequest()
+	_, err := c.Register(ctx, registerReq)
+	require.NoError(t, err)
+	_, err = c.Login(ctx, &up.LoginRequest{
+		Username: registerReq.Username,
+		Password: registerReq.Password,
+	})
+	require.NoError(t, err)
+
+	createMovieResp, err := c.CreateMovie(ctx, &up.CreateMovieRequest{
+		Name: "movie-" + idutil.NewID(),
+		Link: "https://www.youtube.com/watch?v=" + idutil.NewID(),
+	})
+	require.NoError(t, err)
+
+	// the listing is public
+	resp, err := client.New(serverURL).ListMoviesByUsername(ctx, &up.ListMoviesByUsernameRequest{Username: registerReq.Username})
+	require.NoError(t, err)
+	assert.Equal(t, registerReq.Name, resp.User.Name)
+	assert.Equal(t, 1, resp.User.Shares)
+	require.Len(t, resp.Movies, 1)
+	assert.Equal(t, createMovieResp.ID, resp.Movies[0].ID)
+	assert.Equal(t, registerReq.Username, resp.Movies[0].SharedByUsername)
+
+	page, err := http.Get(serverURL + "/u/" + registerReq.Username)
+	require.NoError(t, err)
+	defer page.Body.Close()
+	assert.Equal(t, http.StatusOK, page.StatusCode)
+}
@@ -0,0 +1,78 @@
+package features
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"remi/pkg/client"
+	"remi/pkg/golibs/idutil"
+	"remi/up"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaylistService_Items_Success(t *testing.T) {
+	c := newLoggedInClient(t)
+	ctx := context.Background()
+
+	createPlaylistResp, err := c.CreatePlaylist(ctx, &up.CreatePlaylistRequest{
+		Name:       "playlist-" + idutil.NewID(),
+		Visibility: up.PlaylistPublic,
+	})
+	require.NoError(t, err)
+
+	var movieIDs []string
+	for i := 0; i < 2; i++ {
+		createMovieResp, err := c.CreateMovie(ctx, &up.CreateMovieRequest{
+			Name: "movie-" + idutil.NewID(),
+			Link: "https://www.youtube.com/watch?v=" + idutil.NewID(),
+		})
+		require.NoError(t, err)
+
+		_, err = c.AddPlaylistItem(ctx, &up.AddPlaylistItemRequest{
+			PlaylistID: createPlaylistResp.ID,
+			MovieID:    createMovieResp.ID,
+		})
+		require.NoError(t, err)
+		movieIDs = append(movieIDs, createMovieResp.ID)
+	}
+
+	reorderResp, err := c.ReorderPlaylistItems(ctx, &up.ReorderPlaylistItemsRequest{
+		PlaylistID: createPlaylistResp.ID,
+		MovieIDs:   []string{movieIDs[1], movieIDs[0]},
+	})
+	require.NoError(t, err)
+	require.Len(t, reorderResp.Items, 2)
+	assert.Equal(t, movieIDs[1], reorderResp.Items[0].ID)
+	assert.Equal(t, movieIDs[0], reorderResp.Items[1].ID)
+
+	removeResp, err := c.RemovePlaylistItem(ctx, &up.RemovePlaylistItemRequest{
+		PlaylistID: createPlaylistResp.ID,
+		MovieID:    movieIDs[1],
+	})
+	require.NoError(t, err)
+	require.Len(t, removeResp.Items, 1)
+	assert.Equal(t, movieIDs[0], removeResp.Items[0].ID)
+}
+
+func TestPlaylistService_GetPlaylist_Private(t *testing.T) {
+	owner := newLoggedInClient(t)
+	ctx := context.Background()
+
+	createPlaylistResp, err := owner.CreatePlaylist(ctx, &up.CreatePlaylistRequest{
+		Name: "playlist-" + idutil.NewID(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, up.PlaylistPrivate, createPlaylistResp.Visibility)
+
+	_, err = owner.GetPlaylist(ctx, &up.GetPlaylistRequest{ID: createPlaylistResp.ID})
+	require.NoError(t, err)
+
+	_, err = newLoggedInClient(t).GetPlaylist(ctx, &up.GetPlaylistRequest{ID: createPlaylistResp.ID})
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
@@ -0,0 +1,105 @@
+package features
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	"remi/pkg/client"
+	"remi/up"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onePixelPNG is a 1x1 transparent PNG image
+var onePixelPNG, _ = base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII=")
+
+func TestUserService_UpdateProfile_Success(t *testing.T) {
+	c := newLoggedInClient(t)
+	ctx := context.Background()
+
+	name := "renamed"
+	updateResp, err := c.UpdateProfile(ctx, &up.UpdateProfileRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, name, updateResp.Name)
+
+	getResp, err := c.GetProfile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, name, getResp.Name)
+	assert.Empty(t, getResp.AvatarURL)
+}
+
+func TestUserService_ChangePassword_Success(t *testing.T) {
+	c := client.New(serverURL)
+	ctx := context.Background()
+
+	registerReq := newRegisterRequest()
+	_, err := c.Register(ctx, registerReq)
+	require.NoError(t, err)
+	loginResp, err := c.Login(ctx, &up.LoginRequest{Username: registerReq.Username, Password: registerReq.Password})
+	require.NoError(t, err)
+	otherSession := client.New(serverURL)
+	_, err = otherSession.Login(ctx, &up.LoginRequest{Username: registerReq.Username, Password: registerReq.Password})
+	require.NoError(t, err)
+
+	_, err = c.ChangePassword(ctx, &up.ChangePasswordRequest{
+		CurrentPassword: registerReq.Password,
+		NewPassword:     "new-password-1",
+	})
+	require.NoError(t, err)
+
+	// the session keeps working with the new tokens, the old refresh token is revoked
+	_, err = c.GetProfile(ctx)
+	require.NoError(t, err)
+	_, err = client.New(serverURL).Refresh(ctx, &up.RefreshRequest{RefreshToken: loginResp.RefreshToken})
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+
+	// the access tokens of the other sessions are rejected
+	_, err = otherSession.GetProfile(ctx)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+
+	_, err = client.New(serverURL).Login(ctx, &up.LoginRequest{Username: registerReq.Username, Password: "new-password-1"})
+	assert.NoError(t, err)
+}
+
+func TestUserService_ChangePassword_Error(t *testing.T) {
+	c := newLoggedInClient(t)
+
+	_, err := c.ChangePassword(context.Background(), &up.ChangePasswordRequest{
+		CurrentPassword: "wrong-password-1",
+		NewPassword:     "new-password-1",
+	})
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	require.Len(t, apiErr.Details, 1)
+	assert.Equal(t, "current_password", apiErr.Details[0].Field)
+}
+
+func TestUserService_UploadAvatar_Success(t *testing.T) {
+	c := newLoggedInClient(t)
+	ctx := context.Background()
+
+	uploadResp, err := c.UploadAvatar(ctx, &up.UploadAvatarRequest{Image: onePixelPNG})
+	require.NoError(t, err)
+	require.NotEmpty(t, uploadResp.AvatarURL)
+
+	// the avatar URL is absolute when the server is configured with its URL
+	avatarPath := uploadResp.AvatarURL[strings.Index(uploadResp.AvatarURL, "/avatars/"):]
+	avatarResp, err := http.Get(serverURL + avatarPath)
+	require.NoError(t, err)
+	defer avatarResp.Body.Close()
+	assert.Equal(t, http.StatusOK, avatarResp.StatusCode)
+	assert.Equal(t, "image/png", avatarResp.Header.Get("Content-Type"))
+
+	deleteResp, err := c.DeleteAvatar(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, deleteResp.AvatarURL)
+}
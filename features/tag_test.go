@@ -0,0 +1,61 @@
+package features
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"remi/pkg/client"
+	"remi/pkg/golibs/idutil"
+	"remi/up"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_ListMovies_Tags(t *testing.T) {
+	c := newLoggedInClient(t)
+	ctx := context.Background()
+
+	// unique tags so the movies of other tests don't match
+	tagA, tagB := "a"+idutil.NewID(), "b"+idutil.NewID()
+	var movieIDs []string
+	for _, tags := range [][]string{{tagA}, {"#" + tagA, tagB + " "}} {
+		createMovieResp, err := c.CreateMovie(ctx, &up.CreateMovieRequest{
+			Name: "movie-" + idutil.NewID(),
+			Link: "https://www.youtube.com/watch?v=" + idutil.NewID(),
+			Tags: tags,
+		})
+		require.NoError(t, err)
+		movieIDs = append(movieIDs, createMovieResp.ID)
+	}
+
+	anyResp, err := c.ListMovies(ctx, &up.ListMoviesRequest{Tags: []string{tagA, tagB}})
+	require.NoError(t, err)
+	require.Len(t, anyResp.Movies, 2)
+	assert.Equal(t, movieIDs[1], anyResp.Movies[0].ID)
+	assert.ElementsMatch(t, []string{tagA, tagB}, anyResp.Movies[0].Tags)
+
+	allResp, err := c.ListMovies(ctx, &up.ListMoviesRequest{Tags: []string{tagA, tagB}, TagMode: up.TagModeAll})
+	require.NoError(t, err)
+	require.Len(t, allResp.Movies, 1)
+	assert.Equal(t, movieIDs[1], allResp.Movies[0].ID)
+
+	limit := 200
+	tagsResp, err := c.ListTags(ctx, &up.ListTagsRequest{Limit: &limit})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tagsResp.Tags)
+}
+
+func TestMovieService_CreateMovie_InvalidTag(t *testing.T) {
+	c := newLoggedInClient(t)
+
+	_, err := c.CreateMovie(context.Background(), &up.CreateMovieRequest{
+		Link: "https://www.youtube.com/watch?v=" + idutil.NewID(),
+		Tags: []string{"not/valid"},
+	})
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
@@ -0,0 +1,73 @@
+package features
+
+import (
+	"context"
+	"testing"
+
+	"remi/pkg/golibs/idutil"
+	"remi/up"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchService_WatchLater_Success(t *testing.T) {
+	c := newLoggedInClient(t)
+	ctx := context.Background()
+
+	createMovieResp, err := c.CreateMovie(ctx, &up.CreateMovieRequest{
+		Name: "movie-" + idutil.NewID(),
+		Link: "https://www.youtube.com/watch?v=" + idutil.NewID(),
+	})
+	require.NoError(t, err)
+
+	_, err = c.AddWatchLater(ctx, &up.AddWatchLaterRequest{MovieID: createMovieResp.ID})
+	require.NoError(t, err)
+
+	listResp, err := c.ListWatchLater(ctx, &up.ListWatchLaterRequest{})
+	require.NoError(t, err)
+	require.Len(t, listResp.Items, 1)
+	assert.Equal(t, createMovieResp.ID, listResp.Items[0].Movie.ID)
+
+	_, err = c.RemoveWatchLater(ctx, &up.RemoveWatchLaterRequest{MovieID: createMovieResp.ID})
+	require.NoError(t, err)
+
+	listResp, err = c.ListWatchLater(ctx, &up.ListWatchLaterRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, listResp.Items)
+}
+
+func TestWatchService_WatchHistory_Success(t *testing.T) {
+	c := newLoggedInClient(t)
+	ctx := context.Background()
+
+	createMovieResp, err := c.CreateMovie(ctx, &up.CreateMovieRequest{
+		Name: "movie-" + idutil.NewID(),
+		Link: "https://www.youtube.com/watch?v=" + idutil.NewID(),
+	})
+	require.NoError(t, err)
+
+	getResp, err := c.GetMovie(ctx, &up.GetMovieRequest{ID: createMovieResp.ID})
+	require.NoError(t, err)
+	assert.False(t, getResp.Watched)
+
+	_, err = c.RecordWatch(ctx, &up.RecordWatchRequest{MovieID: createMovieResp.ID})
+	require.NoError(t, err)
+
+	historyResp, err := c.ListWatchHistory(ctx, &up.ListWatchHistoryRequest{})
+	require.NoError(t, err)
+	require.Len(t, historyResp.Items, 1)
+	assert.Equal(t, createMovieResp.ID, historyResp.Items[0].Movie.ID)
+	assert.True(t, historyResp.Items[0].Movie.Watched)
+
+	getResp, err = c.GetMovie(ctx, &up.GetMovieRequest{ID: createMovieResp.ID})
+	require.NoError(t, err)
+	assert.True(t, getResp.Watched)
+
+	_, err = c.RemoveWatchHistory(ctx, &up.RemoveWatchHistoryRequest{MovieID: createMovieResp.ID})
+	require.NoError(t, err)
+
+	historyResp, err = c.ListWatchHistory(ctx, &up.ListWatchHistoryRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, historyResp.Items)
+}
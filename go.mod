@@ -1,11 +1,12 @@
 module remi
 
-go 1.17
+go 1.18
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.5.0
 	github.com/smartystreets/goconvey v1.7.2
 	golang.org/x/crypto v0.1.0
+	golang.org/x/term v0.1.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
@@ -24,6 +25,7 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.1.0 // indirect
 	github.com/pressly/goose/v3 v3.7.0
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
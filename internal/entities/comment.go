@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// Comment reflects comments data from DB, replies have a ParentID
+type Comment struct {
+	ID        string
+	MovieID   string
+	UserID    string
+	ParentID  *string
+	Content   string
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+	DeletedAt *time.Time
+}
+
+type Comments []*Comment
+
+func (e *Comment) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"id",
+			"movie_id",
+			"user_id",
+			"parent_id",
+			"content",
+			"created_at",
+			"updated_at",
+			"deleted_at",
+		}, []interface{}{
+			&e.ID,
+			&e.MovieID,
+			&e.UserID,
+			&e.ParentID,
+			&e.Content,
+			&e.CreatedAt,
+			&e.UpdatedAt,
+			&e.DeletedAt,
+		}
+}
+
+func (e *Comment) TableName() string {
+	return "comments"
+}
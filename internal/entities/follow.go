@@ -0,0 +1,28 @@
+package entities
+
+import "time"
+
+// Follow reflects follows data from DB, FollowerID follows FolloweeID
+type Follow struct {
+	FollowerID string
+	FolloweeID string
+	CreatedAt  *time.Time
+}
+
+type Follows []*Follow
+
+func (e *Follow) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"follower_id",
+			"followee_id",
+			"created_at",
+		}, []interface{}{
+			&e.FollowerID,
+			&e.FolloweeID,
+			&e.CreatedAt,
+		}
+}
+
+func (e *Follow) TableName() string {
+	return "follows"
+}
@@ -0,0 +1,74 @@
+package entities
+
+import "time"
+
+const (
+	LoginScopeUsername = "username"
+	LoginScopeIP       = "ip"
+)
+
+// LoginAttempt reflects login_attempts data from DB, it counts the recent failed logins
+// of a username or of an IP depending on Scope
+type LoginAttempt struct {
+	Scope        string
+	Subject      string
+	Failures     int
+	LastFailedAt *time.Time
+	LockedUntil  *time.Time
+}
+
+func (e *LoginAttempt) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"scope",
+			"subject",
+			"failures",
+			"last_failed_at",
+			"locked_until",
+		}, []interface{}{
+			&e.Scope,
+			&e.Subject,
+			&e.Failures,
+			&e.LastFailedAt,
+			&e.LockedUntil,
+		}
+}
+
+func (e *LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// LoginLockout reflects login_lockouts data from DB, the audit of every lockout.
+// IP is the address of the failed login which triggered the lockout.
+type LoginLockout struct {
+	ID          string
+	Scope       string
+	Subject     string
+	IP          string
+	Failures    int
+	LockedUntil *time.Time
+	CreatedAt   *time.Time
+}
+
+func (e *LoginLockout) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"id",
+			"scope",
+			"subject",
+			"ip",
+			"failures",
+			"locked_until",
+			"created_at",
+		}, []interface{}{
+			&e.ID,
+			&e.Scope,
+			&e.Subject,
+			&e.IP,
+			&e.Failures,
+			&e.LockedUntil,
+			&e.CreatedAt,
+		}
+}
+
+func (e *LoginLockout) TableName() string {
+	return "login_lockouts"
+}
@@ -9,6 +9,9 @@ type Movie struct {
 	Description string
 	Link        string
 	Thumbnail   string
+	Provider    string
+	Author      string
+	Duration    int
 	SharedBy    string
 	SharedAt    *time.Time
 	CreatedAt   *time.Time
@@ -25,6 +28,9 @@ func (e *Movie) FieldMap() (fields []string, values []interface{}) {
 			"description",
 			"link",
 			"thumbnail",
+			"provider",
+			"author",
+			"duration",
 			"shared_by",
 			"shared_at",
 			"created_at",
@@ -36,6 +42,9 @@ func (e *Movie) FieldMap() (fields []string, values []interface{}) {
 			&e.Description,
 			&e.Link,
 			&e.Thumbnail,
+			&e.Provider,
+			&e.Author,
+			&e.Duration,
 			&e.SharedBy,
 			&e.SharedAt,
 			&e.CreatedAt,
@@ -47,3 +56,11 @@ func (e *Movie) FieldMap() (fields []string, values []interface{}) {
 func (e *Movie) TableName() string {
 	return "movies"
 }
+
+// MovieSearchResult is a movie matching a full-text search with its highlighted fields
+type MovieSearchResult struct {
+	Movie
+	Rank                 float64
+	NameHighlight        string
+	DescriptionHighlight string
+}
@@ -0,0 +1,76 @@
+package entities
+
+import "time"
+
+const (
+	PlaylistPublic  = "public"
+	PlaylistPrivate = "private"
+)
+
+// Playlist reflects playlists data from DB, private playlists are only visible to their owner
+type Playlist struct {
+	ID          string
+	UserID      string
+	Name        string
+	Description string
+	Visibility  string
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+	DeletedAt   *time.Time
+}
+
+type Playlists []*Playlist
+
+func (e *Playlist) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"id",
+			"user_id",
+			"name",
+			"description",
+			"visibility",
+			"created_at",
+			"updated_at",
+			"deleted_at",
+		}, []interface{}{
+			&e.ID,
+			&e.UserID,
+			&e.Name,
+			&e.Description,
+			&e.Visibility,
+			&e.CreatedAt,
+			&e.UpdatedAt,
+			&e.DeletedAt,
+		}
+}
+
+func (e *Playlist) TableName() string {
+	return "playlists"
+}
+
+// PlaylistItem reflects playlist_items data from DB, items are played by ascending Position
+type PlaylistItem struct {
+	PlaylistID string
+	MovieID    string
+	Position   int
+	AddedAt    *time.Time
+}
+
+type PlaylistItems []*PlaylistItem
+
+func (e *PlaylistItem) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"playlist_id",
+			"movie_id",
+			"position",
+			"added_at",
+		}, []interface{}{
+			&e.PlaylistID,
+			&e.MovieID,
+			&e.Position,
+			&e.AddedAt,
+		}
+}
+
+func (e *PlaylistItem) TableName() string {
+	return "playlist_items"
+}
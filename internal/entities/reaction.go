@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+const (
+	ReactionLike    = "like"
+	ReactionDislike = "dislike"
+)
+
+// Reaction reflects movie_reactions data from DB
+type Reaction struct {
+	MovieID   string
+	UserID    string
+	Type      string
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+type Reactions []*Reaction
+
+func (e *Reaction) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"movie_id",
+			"user_id",
+			"type",
+			"created_at",
+			"updated_at",
+		}, []interface{}{
+			&e.MovieID,
+			&e.UserID,
+			&e.Type,
+			&e.CreatedAt,
+			&e.UpdatedAt,
+		}
+}
+
+func (e *Reaction) TableName() string {
+	return "movie_reactions"
+}
+
+// ReactionCount is the number of likes and dislikes of a movie
+type ReactionCount struct {
+	MovieID  string
+	Likes    int
+	Dislikes int
+}
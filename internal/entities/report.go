@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// Report reflects movie_reports data from DB
+type Report struct {
+	ID         string
+	MovieID    string
+	UserID     string
+	Reason     string
+	CreatedAt  *time.Time
+	ResolvedAt *time.Time
+}
+
+type Reports []*Report
+
+func (e *Report) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"id",
+			"movie_id",
+			"user_id",
+			"reason",
+			"created_at",
+			"resolved_at",
+		}, []interface{}{
+			&e.ID,
+			&e.MovieID,
+			&e.UserID,
+			&e.Reason,
+			&e.CreatedAt,
+			&e.ResolvedAt,
+		}
+}
+
+func (e *Report) TableName() string {
+	return "movie_reports"
+}
@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// Tag reflects tags data from DB, names are normalized and unique
+type Tag struct {
+	ID        string
+	Name      string
+	CreatedAt *time.Time
+}
+
+type Tags []*Tag
+
+func (e *Tag) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"id",
+			"name",
+			"created_at",
+		}, []interface{}{
+			&e.ID,
+			&e.Name,
+			&e.CreatedAt,
+		}
+}
+
+func (e *Tag) TableName() string {
+	return "tags"
+}
+
+// MovieTag reflects movie_tags data from DB
+type MovieTag struct {
+	MovieID   string
+	TagID     string
+	CreatedAt *time.Time
+}
+
+func (e *MovieTag) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"movie_id",
+			"tag_id",
+			"created_at",
+		}, []interface{}{
+			&e.MovieID,
+			&e.TagID,
+			&e.CreatedAt,
+		}
+}
+
+func (e *MovieTag) TableName() string {
+	return "movie_tags"
+}
+
+// TagCount is the number of visible movies with a tag
+type TagCount struct {
+	Name  string
+	Count int
+}
@@ -0,0 +1,65 @@
+package entities
+
+import "time"
+
+// RefreshToken reflects refresh_tokens data from DB.
+// Tokens issued by rotating each other share a FamilyID.
+type RefreshToken struct {
+	ID         string
+	UserID     string
+	TokenHash  string
+	FamilyID   string
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *string
+	CreatedAt  *time.Time
+}
+
+func (e *RefreshToken) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"id",
+			"user_id",
+			"token_hash",
+			"family_id",
+			"expires_at",
+			"revoked_at",
+			"replaced_by",
+			"created_at",
+		}, []interface{}{
+			&e.ID,
+			&e.UserID,
+			&e.TokenHash,
+			&e.FamilyID,
+			&e.ExpiresAt,
+			&e.RevokedAt,
+			&e.ReplacedBy,
+			&e.CreatedAt,
+		}
+}
+
+func (e *RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RevokedToken reflects revoked_tokens data from DB, access tokens are revoked by their jti claim
+type RevokedToken struct {
+	JTI       string
+	ExpiresAt *time.Time
+	CreatedAt *time.Time
+}
+
+func (e *RevokedToken) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"jti",
+			"expires_at",
+			"created_at",
+		}, []interface{}{
+			&e.JTI,
+			&e.ExpiresAt,
+			&e.CreatedAt,
+		}
+}
+
+func (e *RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -2,14 +2,26 @@ package entities
 
 import "time"
 
-// User reflects users data from DB
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// User reflects users data from DB, DisabledAt is set when the account is banned.
+// AvatarKey is the key of the avatar image in the blob store.
+// Access tokens issued before TokensValidAfter are rejected, e.g. after a password change.
 type User struct {
-	ID        string
-	Username  string
-	Password  string
-	Name      string
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
+	ID               string
+	Username         string
+	Password         string
+	Name             string
+	Role             string
+	DisabledAt       *time.Time
+	AvatarKey        *string
+	TokensValidAfter *time.Time
+	CreatedAt        *time.Time
+	UpdatedAt        *time.Time
 }
 
 type Users []*User
@@ -20,6 +32,10 @@ func (e *User) FieldMap() (fields []string, values []interface{}) {
 			"username",
 			"password",
 			"name",
+			"role",
+			"disabled_at",
+			"avatar_key",
+			"tokens_valid_after",
 			"created_at",
 			"updated_at",
 		}, []interface{}{
@@ -27,6 +43,10 @@ func (e *User) FieldMap() (fields []string, values []interface{}) {
 			&e.Username,
 			&e.Password,
 			&e.Name,
+			&e.Role,
+			&e.DisabledAt,
+			&e.AvatarKey,
+			&e.TokensValidAfter,
 			&e.CreatedAt,
 			&e.UpdatedAt,
 		}
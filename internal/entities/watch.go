@@ -0,0 +1,53 @@
+package entities
+
+import "time"
+
+// WatchLaterItem reflects watch_later data from DB, a movie the user saved to watch later
+type WatchLaterItem struct {
+	UserID  string
+	MovieID string
+	AddedAt *time.Time
+}
+
+type WatchLaterItems []*WatchLaterItem
+
+func (e *WatchLaterItem) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"user_id",
+			"movie_id",
+			"added_at",
+		}, []interface{}{
+			&e.UserID,
+			&e.MovieID,
+			&e.AddedAt,
+		}
+}
+
+func (e *WatchLaterItem) TableName() string {
+	return "watch_later"
+}
+
+// WatchHistoryItem reflects watch_history data from DB, WatchedAt is the last time the user viewed the movie
+type WatchHistoryItem struct {
+	UserID    string
+	MovieID   string
+	WatchedAt *time.Time
+}
+
+type WatchHistoryItems []*WatchHistoryItem
+
+func (e *WatchHistoryItem) FieldMap() (fields []string, values []interface{}) {
+	return []string{
+			"user_id",
+			"movie_id",
+			"watched_at",
+		}, []interface{}{
+			&e.UserID,
+			&e.MovieID,
+			&e.WatchedAt,
+		}
+}
+
+func (e *WatchHistoryItem) TableName() string {
+	return "watch_history"
+}
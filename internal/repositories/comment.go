@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/golibs/database"
+
+	"github.com/lib/pq"
+)
+
+type CommentRepository struct {
+	*sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{
+		db,
+	}
+}
+
+func (r *CommentRepository) Create(ctx context.Context, c *entities.Comment) error {
+	fields, values := c.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)`, c.TableName(), strings.Join(fields, ","), placeHolders)
+	result, err := r.DB.ExecContext(ctx, stmt, values...)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't insert comment")
+	}
+
+	return nil
+}
+
+// FindByID find comment by id
+func (r *CommentRepository) FindByID(ctx context.Context, id string) (*entities.Comment, error) {
+	comment := &entities.Comment{}
+	fields, values := comment.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 AND deleted_at IS NULL`, strings.Join(fields, ","), comment.TableName())
+	row := r.QueryRowContext(ctx, stmt, id)
+
+	if err := row.Scan(values...); err != nil {
+		return nil, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return comment, nil
+}
+
+// UpdateContent edits a comment owned by the comment's UserID
+func (r *CommentRepository) UpdateContent(ctx context.Context, c *entities.Comment) error {
+	stmt := fmt.Sprintf(`UPDATE %s SET content = $1, updated_at = $2
+	WHERE id = $3 AND user_id = $4 AND deleted_at IS NULL`, c.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, c.Content, c.UpdatedAt, c.ID, c.UserID)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't update comment")
+	}
+
+	return nil
+}
+
+// SoftDelete marks a comment owned by userID as deleted
+func (r *CommentRepository) SoftDelete(ctx context.Context, id, userID string, deletedAt time.Time) error {
+	comment := &entities.Comment{}
+	stmt := fmt.Sprintf(`UPDATE %s SET deleted_at = $1, updated_at = $1
+	WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL`, comment.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, deletedAt, id, userID)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't delete comment")
+	}
+
+	return nil
+}
+
+type ListCommentsArgs struct {
+	MovieID string
+	// After is the last comment of the previous page, nil for the first page
+	After *cursor.Cursor
+	Limit int
+}
+
+// List find top level comments of a movie, newest first
+func (r *CommentRepository) List(ctx context.Context, args *ListCommentsArgs) (cs entities.Comments, _ error) {
+	comment := &entities.Comment{}
+	fields, _ := comment.FieldMap()
+
+	var afterCreatedAt *time.Time
+	var afterID *string
+	if args.After != nil {
+		afterCreatedAt = &args.After.CreatedAt
+		afterID = &args.After.ID
+	}
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE movie_id = $1 AND parent_id IS NULL AND deleted_at IS NULL AND
+	($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT))
+	ORDER BY created_at DESC, id DESC
+	LIMIT $4`, strings.Join(fields, ","), comment.TableName())
+	rows, err := r.QueryContext(ctx, stmt, args.MovieID, afterCreatedAt, afterID, args.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	return scanComments(rows)
+}
+
+// ListReplies find at most limit replies of each of the given comments, oldest first
+func (r *CommentRepository) ListReplies(ctx context.Context, parentIDs []string, limit int) (cs entities.Comments, _ error) {
+	comment := &entities.Comment{}
+	fields, _ := comment.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM (
+		SELECT %s, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY created_at ASC, id ASC) AS rank FROM %s
+		WHERE parent_id = ANY($1::_TEXT) AND deleted_at IS NULL
+	) AS replies
+	WHERE rank <= $2
+	ORDER BY created_at ASC, id ASC`, strings.Join(fields, ","), strings.Join(fields, ","), comment.TableName())
+	rows, err := r.QueryContext(ctx, stmt, pq.StringArray(parentIDs), limit)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	return scanComments(rows)
+}
+
+type ListRepliesArgs struct {
+	ParentID string
+	// After is the last reply of the previous page, nil for the first page
+	After *cursor.Cursor
+	Limit int
+}
+
+// ListRepliesOf find the replies of a comment, oldest first
+func (r *CommentRepository) ListRepliesOf(ctx context.Context, args *ListRepliesArgs) (cs entities.Comments, _ error) {
+	comment := &entities.Comment{}
+	fields, _ := comment.FieldMap()
+
+	var afterCreatedAt *time.Time
+	var afterID *string
+	if args.After != nil {
+		afterCreatedAt = &args.After.CreatedAt
+		afterID = &args.After.ID
+	}
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE parent_id = $1 AND deleted_at IS NULL AND
+	($2::TIMESTAMPTZ IS NULL OR (created_at, id) > ($2::TIMESTAMPTZ, $3::TEXT))
+	ORDER BY created_at ASC, id ASC
+	LIMIT $4`, strings.Join(fields, ","), comment.TableName())
+	rows, err := r.QueryContext(ctx, stmt, args.ParentID, afterCreatedAt, afterID, args.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	return scanComments(rows)
+}
+
+func scanComments(rows *sql.Rows) (cs entities.Comments, _ error) {
+	defer rows.Close()
+	for rows.Next() {
+		c := &entities.Comment{}
+		_, values := c.FieldMap()
+		err := rows.Scan(values...)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		cs = append(cs, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return cs, nil
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/golibs/idutil"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentRepository_Create(t *testing.T) {
+	db, mock := NewMock()
+	repo := CommentRepository{DB: db}
+
+	now := time.Now()
+	parentID := "parent-id"
+	c := &entities.Comment{
+		ID:        idutil.NewID(),
+		MovieID:   "movie-id",
+		UserID:    "user-id",
+		ParentID:  &parentID,
+		Content:   "content",
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         c,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO comments(id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")).
+					WithArgs(c.ID, c.MovieID, c.UserID, c.ParentID, c.Content, c.CreatedAt, c.UpdatedAt, c.DeletedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "no row affected",
+			req:         c,
+			expectedErr: fmt.Errorf("can't insert comment"),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO comments(id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")).
+					WithArgs(c.ID, c.MovieID, c.UserID, c.ParentID, c.Content, c.CreatedAt, c.UpdatedAt, c.DeletedAt).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Create(ctx, testCase.req.(*entities.Comment))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestCommentRepository_List(t *testing.T) {
+	db, mock := NewMock()
+	repo := CommentRepository{DB: db}
+
+	after := &cursor.Cursor{CreatedAt: time.Now(), ID: "last-id"}
+	args := &ListCommentsArgs{
+		MovieID: "movie-id",
+		After:   after,
+		Limit:   10,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at FROM comments WHERE movie_id = $1 AND parent_id IS NULL AND deleted_at IS NULL AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT)) ORDER BY created_at DESC, id DESC LIMIT $4")).
+					WithArgs(args.MovieID, &after.CreatedAt, &after.ID, args.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "movie_id", "user_id", "parent_id", "content", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "movie-id", "user-id", nil, "content", time.Now(), time.Now(), nil))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         args,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at FROM comments WHERE movie_id = $1 AND parent_id IS NULL AND deleted_at IS NULL AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT)) ORDER BY created_at DESC, id DESC LIMIT $4")).
+					WithArgs(args.MovieID, &after.CreatedAt, &after.ID, args.Limit).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		comments, err := repo.List(ctx, testCase.req.(*ListCommentsArgs))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Len(t, comments, 1)
+		}
+	}
+}
+
+func TestCommentRepository_ListReplies(t *testing.T) {
+	db, mock := NewMock()
+	repo := CommentRepository{DB: db}
+
+	parentIDs := []string{"parent-1", "parent-2"}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         parentIDs,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at FROM ( SELECT id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY created_at ASC, id ASC) AS rank FROM comments WHERE parent_id = ANY($1::_TEXT) AND deleted_at IS NULL ) AS replies WHERE rank <= $2 ORDER BY created_at ASC, id ASC")).
+					WithArgs(pq.StringArray(parentIDs), 21).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "movie_id", "user_id", "parent_id", "content", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "movie-id", "user-id", "parent-1", "content", time.Now(), time.Now(), nil))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         parentIDs,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at FROM ( SELECT id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY created_at ASC, id ASC) AS rank FROM comments WHERE parent_id = ANY($1::_TEXT) AND deleted_at IS NULL ) AS replies WHERE rank <= $2 ORDER BY created_at ASC, id ASC")).
+					WithArgs(pq.StringArray(parentIDs), 21).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		comments, err := repo.ListReplies(ctx, testCase.req.([]string), 21)
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, "parent-1", *comments[0].ParentID)
+		}
+	}
+}
+
+func TestCommentRepository_ListRepliesOf(t *testing.T) {
+	db, mock := NewMock()
+	repo := CommentRepository{DB: db}
+
+	after := &cursor.Cursor{CreatedAt: time.Now(), ID: "last-id"}
+	args := &ListRepliesArgs{
+		ParentID: "parent-1",
+		After:    after,
+		Limit:    10,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at FROM comments WHERE parent_id = $1 AND deleted_at IS NULL AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) > ($2::TIMESTAMPTZ, $3::TEXT)) ORDER BY created_at ASC, id ASC LIMIT $4")).
+					WithArgs(args.ParentID, &after.CreatedAt, &after.ID, args.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "movie_id", "user_id", "parent_id", "content", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "movie-id", "user-id", "parent-1", "content", time.Now(), time.Now(), nil))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         args,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,movie_id,user_id,parent_id,content,created_at,updated_at,deleted_at FROM comments WHERE parent_id = $1 AND deleted_at IS NULL AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) > ($2::TIMESTAMPTZ, $3::TEXT)) ORDER BY created_at ASC, id ASC LIMIT $4")).
+					WithArgs(args.ParentID, &after.CreatedAt, &after.ID, args.Limit).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		replies, err := repo.ListRepliesOf(ctx, testCase.req.(*ListRepliesArgs))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, "parent-1", *replies[0].ParentID)
+		}
+	}
+}
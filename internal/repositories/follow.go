@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/golibs/database"
+)
+
+type FollowRepository struct {
+	*sql.DB
+}
+
+func NewFollowRepository(db *sql.DB) *FollowRepository {
+	return &FollowRepository{
+		db,
+	}
+}
+
+// Create follows the followee, it's a no-op when the follower already follows them
+func (r *FollowRepository) Create(ctx context.Context, f *entities.Follow) error {
+	fields, values := f.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)
+	ON CONFLICT (follower_id, followee_id) DO NOTHING`, f.TableName(), strings.Join(fields, ","), placeHolders)
+	if _, err := r.DB.ExecContext(ctx, stmt, values...); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete unfollows the followee, it's a no-op when the follower doesn't follow them
+func (r *FollowRepository) Delete(ctx context.Context, followerID, followeeID string) error {
+	follow := &entities.Follow{}
+	stmt := fmt.Sprintf(`DELETE FROM %s WHERE follower_id = $1 AND followee_id = $2`, follow.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, followerID, followeeID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+type ListFollowsArgs struct {
+	UserID string
+	// After is the last follow of the previous page, its ID is the id of the listed user
+	After *cursor.Cursor
+	Limit int
+}
+
+// ListFollowers find the follows of the users following UserID, newest first
+func (r *FollowRepository) ListFollowers(ctx context.Context, args *ListFollowsArgs) (entities.Follows, error) {
+	return r.list(ctx, "followee_id", "follower_id", args)
+}
+
+// ListFollowing find the follows of the users followed by UserID, newest first
+func (r *FollowRepository) ListFollowing(ctx context.Context, args *ListFollowsArgs) (entities.Follows, error) {
+	return r.list(ctx, "follower_id", "followee_id", args)
+}
+
+// list find the follows where userColumn is UserID, paging on the user of listedColumn
+func (r *FollowRepository) list(ctx context.Context, userColumn, listedColumn string, args *ListFollowsArgs) (fs entities.Follows, _ error) {
+	follow := &entities.Follow{}
+	fields, _ := follow.FieldMap()
+
+	var afterCreatedAt *time.Time
+	var afterID *string
+	if args.After != nil {
+		afterCreatedAt = &args.After.CreatedAt
+		afterID = &args.After.ID
+	}
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE %s = $1 AND
+	($2::TIMESTAMPTZ IS NULL OR (created_at, %s) < ($2::TIMESTAMPTZ, $3::TEXT))
+	ORDER BY created_at DESC, %s DESC
+	LIMIT $4`, strings.Join(fields, ","), follow.TableName(), userColumn, listedColumn, listedColumn)
+	rows, err := r.QueryContext(ctx, stmt, args.UserID, afterCreatedAt, afterID, args.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		f := &entities.Follow{}
+		_, values := f.FieldMap()
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		fs = append(fs, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return fs, nil
+}
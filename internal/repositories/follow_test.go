@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"remi/pkg/golibs/cursor"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollowRepository_Create(t *testing.T) {
+	db, mock := NewMock()
+	repo := FollowRepository{DB: db}
+
+	now := time.Now()
+	e := &entities.Follow{
+		FollowerID: "follower-id",
+		FolloweeID: "followee-id",
+		CreatedAt:  &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         e,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO follows(follower_id,followee_id,created_at) VALUES ($1, $2, $3) ON CONFLICT (follower_id, followee_id) DO NOTHING")).
+					WithArgs(e.FollowerID, e.FolloweeID, e.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "already following",
+			req:         e,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO follows(follower_id,followee_id,created_at) VALUES ($1, $2, $3) ON CONFLICT (follower_id, followee_id) DO NOTHING")).
+					WithArgs(e.FollowerID, e.FolloweeID, e.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         e,
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO follows(follower_id,followee_id,created_at) VALUES ($1, $2, $3) ON CONFLICT (follower_id, followee_id) DO NOTHING")).
+					WithArgs(e.FollowerID, e.FolloweeID, e.CreatedAt).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Create(ctx, testCase.req.(*entities.Follow))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestFollowRepository_ListFollowing(t *testing.T) {
+	db, mock := NewMock()
+	repo := FollowRepository{DB: db}
+
+	after := &cursor.Cursor{CreatedAt: time.Now(), ID: "followee-0"}
+	args := &ListFollowsArgs{
+		UserID: "follower-id",
+		After:  after,
+		Limit:  10,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT follower_id,followee_id,created_at FROM follows WHERE follower_id = $1 AND ($2::TIMESTAMPTZ IS NULL OR (created_at, followee_id) < ($2::TIMESTAMPTZ, $3::TEXT)) ORDER BY created_at DESC, followee_id DESC LIMIT $4")).
+					WithArgs(args.UserID, after.CreatedAt, after.ID, args.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"follower_id", "followee_id", "created_at"}).AddRow("follower-id", "followee-1", time.Now()))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         args,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT follower_id,followee_id,created_at FROM follows WHERE follower_id = $1 AND ($2::TIMESTAMPTZ IS NULL OR (created_at, followee_id) < ($2::TIMESTAMPTZ, $3::TEXT)) ORDER BY created_at DESC, followee_id DESC LIMIT $4")).
+					WithArgs(args.UserID, after.CreatedAt, after.ID, args.Limit).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		follows, err := repo.ListFollowing(ctx, testCase.req.(*ListFollowsArgs))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, "followee-1", follows[0].FolloweeID)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/database"
+)
+
+type LoginAttemptRepository struct {
+	*sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db,
+	}
+}
+
+// Find finds the failed logins of the subject, sql.ErrNoRows is returned when there is none
+func (r *LoginAttemptRepository) Find(ctx context.Context, scope, subject string) (*entities.LoginAttempt, error) {
+	attempt := &entities.LoginAttempt{}
+	fields, values := attempt.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE scope = $1 AND subject = $2`, strings.Join(fields, ","), attempt.TableName())
+	row := r.QueryRowContext(ctx, stmt, scope, subject)
+
+	if err := row.Scan(values...); err != nil {
+		return nil, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return attempt, nil
+}
+
+// RecordFailure counts a failed login of the subject and returns the updated counter,
+// the counter starts over when the previous failure happened before resetBefore
+func (r *LoginAttemptRepository) RecordFailure(ctx context.Context, scope, subject string, failedAt, resetBefore time.Time) (*entities.LoginAttempt, error) {
+	attempt := &entities.LoginAttempt{}
+	fields, values := attempt.FieldMap()
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(scope,subject,failures,last_failed_at) VALUES ($1, $2, 1, $3)
+	ON CONFLICT (scope, subject) DO UPDATE SET
+	failures = CASE WHEN %s.last_failed_at < $4 THEN 1 ELSE %s.failures + 1 END, last_failed_at = $3
+	RETURNING %s`, attempt.TableName(), attempt.TableName(), attempt.TableName(), strings.Join(fields, ","))
+	row := r.QueryRowContext(ctx, stmt, scope, subject, failedAt, resetBefore)
+
+	if err := row.Scan(values...); err != nil {
+		return nil, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return attempt, nil
+}
+
+// Lock rejects the logins of the subject until the given time
+func (r *LoginAttemptRepository) Lock(ctx context.Context, scope, subject string, until time.Time) error {
+	attempt := &entities.LoginAttempt{}
+	stmt := fmt.Sprintf(`UPDATE %s SET locked_until = $1 WHERE scope = $2 AND subject = $3`, attempt.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, until, scope, subject); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete forgets the failed logins of the subject
+func (r *LoginAttemptRepository) Delete(ctx context.Context, scope, subject string) error {
+	attempt := &entities.LoginAttempt{}
+	stmt := fmt.Sprintf(`DELETE FROM %s WHERE scope = $1 AND subject = $2`, attempt.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, scope, subject); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+type LoginLockoutRepository struct {
+	*sql.DB
+}
+
+func NewLoginLockoutRepository(db *sql.DB) *LoginLockoutRepository {
+	return &LoginLockoutRepository{
+		db,
+	}
+}
+
+func (r *LoginLockoutRepository) Create(ctx context.Context, l *entities.LoginLockout) error {
+	fields, values := l.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)`, l.TableName(), strings.Join(fields, ","), placeHolders)
+	result, err := r.DB.ExecContext(ctx, stmt, values...)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't insert login lockout")
+	}
+
+	return nil
+}
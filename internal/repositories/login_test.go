@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"remi/pkg/golibs/idutil"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptRepository_RecordFailure(t *testing.T) {
+	db, mock := NewMock()
+	repo := LoginAttemptRepository{DB: db}
+
+	type Args struct {
+		Scope       string
+		Subject     string
+		FailedAt    time.Time
+		ResetBefore time.Time
+	}
+	now := time.Now()
+	args := &Args{
+		Scope:       entities.LoginScopeUsername,
+		Subject:     "username",
+		FailedAt:    now,
+		ResetBefore: now.Add(-24 * time.Hour),
+	}
+	stmt := `INSERT INTO login_attempts(scope,subject,failures,last_failed_at) VALUES ($1, $2, 1, $3)
+	ON CONFLICT (scope, subject) DO UPDATE SET
+	failures = CASE WHEN login_attempts.last_failed_at < $4 THEN 1 ELSE login_attempts.failures + 1 END, last_failed_at = $3
+	RETURNING scope,subject,failures,last_failed_at,locked_until`
+
+	testCases := []TestCase{
+		{
+			name: "happy case",
+			req:  args,
+			expectedResp: &entities.LoginAttempt{
+				Scope:        args.Scope,
+				Subject:      args.Subject,
+				Failures:     3,
+				LastFailedAt: &now,
+			},
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta(stmt)).
+					WithArgs(args.Scope, args.Subject, args.FailedAt, args.ResetBefore).
+					WillReturnRows(sqlmock.NewRows([]string{"scope", "subject", "failures", "last_failed_at", "locked_until"}).
+						AddRow(args.Scope, args.Subject, 3, now, nil))
+			},
+		},
+		{
+			name:        "query error",
+			req:         args,
+			expectedErr: fmt.Errorf("row.Scan: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta(stmt)).
+					WithArgs(args.Scope, args.Subject, args.FailedAt, args.ResetBefore).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		args := testCase.req.(*Args)
+		attempt, err := repo.RecordFailure(ctx, args.Scope, args.Subject, args.FailedAt, args.ResetBefore)
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedResp, attempt)
+		}
+	}
+}
+
+func TestLoginAttemptRepository_Lock(t *testing.T) {
+	db, mock := NewMock()
+	repo := LoginAttemptRepository{DB: db}
+
+	until := time.Now().Add(time.Minute)
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         until,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE login_attempts SET locked_until = $1 WHERE scope = $2 AND subject = $3")).
+					WithArgs(until, entities.LoginScopeIP, "127.0.0.1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         until,
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE login_attempts SET locked_until = $1 WHERE scope = $2 AND subject = $3")).
+					WithArgs(until, entities.LoginScopeIP, "127.0.0.1").
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Lock(ctx, entities.LoginScopeIP, "127.0.0.1", testCase.req.(time.Time))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestLoginLockoutRepository_Create(t *testing.T) {
+	db, mock := NewMock()
+	repo := LoginLockoutRepository{DB: db}
+
+	now := time.Now()
+	lockout := &entities.LoginLockout{
+		ID:          idutil.NewID(),
+		Scope:       entities.LoginScopeUsername,
+		Subject:     "username",
+		IP:          "127.0.0.1",
+		Failures:    5,
+		LockedUntil: &now,
+		CreatedAt:   &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         lockout,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO login_lockouts(id,scope,subject,ip,failures,locked_until,created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)")).
+					WithArgs(lockout.ID, lockout.Scope, lockout.Subject, lockout.IP, lockout.Failures, lockout.LockedUntil, lockout.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         lockout,
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO login_lockouts(id,scope,subject,ip,failures,locked_until,created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)")).
+					WithArgs(lockout.ID, lockout.Scope, lockout.Subject, lockout.IP, lockout.Failures, lockout.LockedUntil, lockout.CreatedAt).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Create(ctx, testCase.req.(*entities.LoginLockout))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
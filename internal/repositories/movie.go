@@ -5,13 +5,17 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"remi/internal/entities"
+	"remi/pkg/golibs/cursor"
 	"remi/pkg/golibs/database"
+
+	"github.com/lib/pq"
 )
 
 type MovieRepository struct {
-	*sql.DB
+	database.DB
 }
 
 func NewMovieRepository(db *sql.DB) *MovieRepository {
@@ -20,6 +24,13 @@ func NewMovieRepository(db *sql.DB) *MovieRepository {
 	}
 }
 
+// WithTx returns a copy of the repository running its queries in tx
+func (r *MovieRepository) WithTx(tx *sql.Tx) *MovieRepository {
+	return &MovieRepository{
+		tx,
+	}
+}
+
 func (r *MovieRepository) Create(ctx context.Context, u *entities.Movie) error {
 	fields, values := u.FieldMap()
 	placeHolders := database.GeneratePlaceholders(len(fields))
@@ -61,7 +72,7 @@ func (r *MovieRepository) FindByID(ctx context.Context, id string) (*entities.Mo
 	movie := &entities.Movie{}
 	fields, values := movie.FieldMap()
 
-	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 AND deleted_at IS NULL`, strings.Join(fields, ","), movie.TableName())
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`, strings.Join(fields, ","), movie.TableName())
 	row := r.QueryRowContext(ctx, stmt, id)
 
 	if err := row.Scan(values...); err != nil {
@@ -71,8 +82,30 @@ func (r *MovieRepository) FindByID(ctx context.Context, id string) (*entities.Mo
 	return movie, nil
 }
 
+// ListByIDs find the visible movies among ids, in no particular order
+func (r *MovieRepository) ListByIDs(ctx context.Context, ids []string) (ms entities.Movies, _ error) {
+	movie := &entities.Movie{}
+	fields, _ := movie.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE id = ANY($1::_TEXT) AND deleted_at IS NULL AND hidden_at IS NULL`, strings.Join(fields, ","), movie.TableName())
+	rows, err := r.QueryContext(ctx, stmt, pq.StringArray(ids))
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	return scanMovies(rows)
+}
+
 type ListMoviesArgs struct {
 	UserID *string
+	// FollowedBy only lists the movies shared by the users followed by FollowedBy
+	FollowedBy *string
+	// Tags only lists the movies with any of the tags, or with all of them when MatchAllTags is set
+	Tags         []string
+	MatchAllTags bool
+	// After is the last movie of the previous page for keyset pagination, Offset is ignored when it's set
+	After  *cursor.Cursor
 	Offset *int
 	Limit  *int
 }
@@ -92,17 +125,43 @@ func (r *MovieRepository) List(ctx context.Context, args *ListMoviesArgs) (ms en
 		offset = *args.Offset
 	}
 
+	var afterCreatedAt *time.Time
+	var afterID *string
+	if args.After != nil {
+		afterCreatedAt = &args.After.CreatedAt
+		afterID = &args.After.ID
+		offset = 0
+	}
+
+	var tags pq.StringArray
+	if len(args.Tags) > 0 {
+		tags = args.Tags
+	}
+
+	tag := &entities.Tag{}
+	movieTag := &entities.MovieTag{}
+	follow := &entities.Follow{}
 	stmt := fmt.Sprintf(`SELECT %s FROM %s 
 	WHERE ($1::TEXT IS NULL OR shared_by = $1::TEXT) AND
-	deleted_at IS NULL
-	ORDER BY created_at DESC
+	($6::TEXT IS NULL OR shared_by IN (SELECT followee_id FROM %s WHERE follower_id = $6::TEXT)) AND
+	($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT)) AND
+	($4::_TEXT IS NULL OR id IN (SELECT mt.movie_id FROM %s mt JOIN %s t ON t.id = mt.tag_id
+	WHERE t.name = ANY($4::_TEXT)
+	GROUP BY mt.movie_id
+	HAVING NOT $5::BOOLEAN OR COUNT(*) = cardinality($4::_TEXT))) AND
+	deleted_at IS NULL AND hidden_at IS NULL
+	ORDER BY created_at DESC, id DESC
 	LIMIT %d
-	OFFSET %d`, strings.Join(fields, ","), movie.TableName(), limit, offset)
-	rows, err := r.QueryContext(ctx, stmt, args.UserID)
+	OFFSET %d`, strings.Join(fields, ","), movie.TableName(), follow.TableName(), movieTag.TableName(), tag.TableName(), limit, offset)
+	rows, err := r.QueryContext(ctx, stmt, args.UserID, afterCreatedAt, afterID, tags, args.MatchAllTags, args.FollowedBy)
 	if err != nil {
 		return nil, fmt.Errorf("r.QueryContext: %w", err)
 	}
 
+	return scanMovies(rows)
+}
+
+func scanMovies(rows *sql.Rows) (ms entities.Movies, _ error) {
 	defer rows.Close()
 	for rows.Next() {
 		m := &entities.Movie{}
@@ -120,3 +179,133 @@ func (r *MovieRepository) List(ctx context.Context, args *ListMoviesArgs) (ms en
 
 	return ms, nil
 }
+
+// CountByUserID counts the visible movies shared by the user
+func (r *MovieRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	movie := &entities.Movie{}
+	stmt := fmt.Sprintf(`SELECT COUNT(*) FROM %s
+	WHERE shared_by = $1 AND deleted_at IS NULL AND hidden_at IS NULL`, movie.TableName())
+
+	var count int
+	if err := r.QueryRowContext(ctx, stmt, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return count, nil
+}
+
+type SearchMoviesArgs struct {
+	Query  string
+	Offset int
+	Limit  int
+}
+
+// Search find movies matching the query with websearch syntax, best matches first.
+// Matched words of the highlights are wrapped in <mark> tags, the rest of the text isn't escaped.
+func (r *MovieRepository) Search(ctx context.Context, args *SearchMoviesArgs) (rs []*entities.MovieSearchResult, _ error) {
+	movie := &entities.Movie{}
+	fields, _ := movie.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s,
+	ts_rank(search_vector, query) AS rank,
+	ts_headline('english', name, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+	ts_headline('english', description, query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')
+	FROM %s, websearch_to_tsquery('english', $1) query
+	WHERE search_vector @@ query AND deleted_at IS NULL AND hidden_at IS NULL
+	ORDER BY rank DESC, created_at DESC, id DESC
+	LIMIT $2
+	OFFSET $3`, strings.Join(fields, ","), movie.TableName())
+	rows, err := r.QueryContext(ctx, stmt, args.Query, args.Limit, args.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		result := &entities.MovieSearchResult{}
+		_, values := result.Movie.FieldMap()
+		values = append(values, &result.Rank, &result.NameHighlight, &result.DescriptionHighlight)
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		rs = append(rs, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return rs, nil
+}
+
+// Update updates name and description of a movie owned by the movie's SharedBy
+func (r *MovieRepository) Update(ctx context.Context, m *entities.Movie) error {
+	stmt := fmt.Sprintf(`UPDATE %s SET name = $1, description = $2, updated_at = $3
+	WHERE id = $4 AND shared_by = $5 AND deleted_at IS NULL`, m.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, m.Name, m.Description, m.UpdatedAt, m.ID, m.SharedBy)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't update movie")
+	}
+
+	return nil
+}
+
+// SoftDelete marks a movie owned by userID as deleted
+func (r *MovieRepository) SoftDelete(ctx context.Context, id, userID string, deletedAt time.Time) error {
+	movie := &entities.Movie{}
+	stmt := fmt.Sprintf(`UPDATE %s SET deleted_at = $1, updated_at = $1
+	WHERE id = $2 AND shared_by = $3 AND deleted_at IS NULL`, movie.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, deletedAt, id, userID)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't delete movie")
+	}
+
+	return nil
+}
+
+// SetHidden hides the movie from everyone when hiddenBy is set, or makes it visible again when it's nil.
+// It returns sql.ErrNoRows when there is no such movie.
+func (r *MovieRepository) SetHidden(ctx context.Context, id string, hiddenBy *string, now time.Time) error {
+	movie := &entities.Movie{}
+
+	var hiddenAt *time.Time
+	if hiddenBy != nil {
+		hiddenAt = &now
+	}
+
+	stmt := fmt.Sprintf(`UPDATE %s SET hidden_at = $1, hidden_by = $2, updated_at = $3
+	WHERE id = $4 AND deleted_at IS NULL`, movie.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, hiddenAt, hiddenBy, now, id)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't hide movie: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
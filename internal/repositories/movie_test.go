@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"regexp"
 	"remi/internal/entities"
+	"remi/pkg/golibs/cursor"
 	"remi/pkg/golibs/idutil"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,6 +27,9 @@ func TestMovieRepository_Create(t *testing.T) {
 		Description: "description of movie-1",
 		Link:        "link of movie-1",
 		Thumbnail:   "thumbnail of movie-1",
+		Provider:    "youtube",
+		Author:      "author of movie-1",
+		Duration:    60,
 		SharedBy:    "1",
 		SharedAt:    &now,
 		CreatedAt:   &now,
@@ -38,8 +43,8 @@ func TestMovieRepository_Create(t *testing.T) {
 			req:         m,
 			expectedErr: nil,
 			setup: func(ctx context.Context) {
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movies(id,name,description,link,thumbnail,shared_by,shared_at,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)")).
-					WithArgs(m.ID, m.Name, m.Description, m.Link, m.Thumbnail, m.SharedBy, m.SharedAt, m.CreatedAt, m.UpdatedAt, m.DeletedAt).
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movies(id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)")).
+					WithArgs(m.ID, m.Name, m.Description, m.Link, m.Thumbnail, m.Provider, m.Author, m.Duration, m.SharedBy, m.SharedAt, m.CreatedAt, m.UpdatedAt, m.DeletedAt).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
@@ -48,8 +53,8 @@ func TestMovieRepository_Create(t *testing.T) {
 			req:         m,
 			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrNoRows),
 			setup: func(ctx context.Context) {
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movies(id,name,description,link,thumbnail,shared_by,shared_at,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)")).
-					WithArgs(m.ID, m.Name, m.Description, m.Link, m.Thumbnail, m.SharedBy, m.SharedAt, m.CreatedAt, m.UpdatedAt, m.DeletedAt).
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movies(id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)")).
+					WithArgs(m.ID, m.Name, m.Description, m.Link, m.Thumbnail, m.Provider, m.Author, m.Duration, m.SharedBy, m.SharedAt, m.CreatedAt, m.UpdatedAt, m.DeletedAt).
 					WillReturnError(sql.ErrNoRows)
 			},
 		},
@@ -58,8 +63,8 @@ func TestMovieRepository_Create(t *testing.T) {
 			req:         m,
 			expectedErr: fmt.Errorf("can't insert movie"),
 			setup: func(ctx context.Context) {
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movies(id,name,description,link,thumbnail,shared_by,shared_at,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)")).
-					WithArgs(m.ID, m.Name, m.Description, m.Link, m.Thumbnail, m.SharedBy, m.SharedAt, m.CreatedAt, m.UpdatedAt, m.DeletedAt).
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movies(id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)")).
+					WithArgs(m.ID, m.Name, m.Description, m.Link, m.Thumbnail, m.Provider, m.Author, m.Duration, m.SharedBy, m.SharedAt, m.CreatedAt, m.UpdatedAt, m.DeletedAt).
 					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 		},
@@ -96,9 +101,9 @@ func TestMovieRepository_FindByIDAndUserID(t *testing.T) {
 			req:         args,
 			expectedErr: nil,
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE id = $1 AND shared_by = $2")).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE id = $1 AND shared_by = $2")).
 					WithArgs(args.ID, args.UserID).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "link", "thumbnail", "shared_by", "shared_at", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "name", "description", "link", "thumbnail", "1", time.Now(), time.Now(), time.Now(), nil))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "link", "thumbnail", "provider", "author", "duration", "shared_by", "shared_at", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "name", "description", "link", "thumbnail", "youtube", "author", 60, "1", time.Now(), time.Now(), time.Now(), nil))
 			},
 		},
 		{
@@ -106,7 +111,7 @@ func TestMovieRepository_FindByIDAndUserID(t *testing.T) {
 			req:         args,
 			expectedErr: fmt.Errorf("row.Scan: %w", sql.ErrNoRows),
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE id = $1 AND shared_by = $2")).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE id = $1 AND shared_by = $2")).
 					WithArgs(args.ID, args.UserID).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -146,9 +151,9 @@ func TestMovieRepository_List(t *testing.T) {
 			req:         args,
 			expectedErr: nil,
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE ($1::TEXT IS NULL OR shared_by = $1::TEXT) AND deleted_at IS NULL ORDER BY created_at DESC LIMIT 5 OFFSET 10")).
-					WithArgs(args.UserID).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "link", "thumbnail", "shared_by", "shared_at", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "name", "description", "link", "thumbnail", "1", time.Now(), time.Now(), time.Now(), nil))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE ($1::TEXT IS NULL OR shared_by = $1::TEXT) AND ($6::TEXT IS NULL OR shared_by IN (SELECT followee_id FROM follows WHERE follower_id = $6::TEXT)) AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT)) AND ($4::_TEXT IS NULL OR id IN (SELECT mt.movie_id FROM movie_tags mt JOIN tags t ON t.id = mt.tag_id WHERE t.name = ANY($4::_TEXT) GROUP BY mt.movie_id HAVING NOT $5::BOOLEAN OR COUNT(*) = cardinality($4::_TEXT))) AND deleted_at IS NULL AND hidden_at IS NULL ORDER BY created_at DESC, id DESC LIMIT 5 OFFSET 10")).
+					WithArgs(args.UserID, nil, nil, nil, false, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "link", "thumbnail", "provider", "author", "duration", "shared_by", "shared_at", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "name", "description", "link", "thumbnail", "youtube", "author", 60, "1", time.Now(), time.Now(), time.Now(), nil))
 			},
 		},
 		{
@@ -156,8 +161,8 @@ func TestMovieRepository_List(t *testing.T) {
 			req:         args,
 			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrNoRows),
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE ($1::TEXT IS NULL OR shared_by = $1::TEXT) AND deleted_at IS NULL ORDER BY created_at DESC LIMIT 5 OFFSET 10")).
-					WithArgs(args.UserID).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE ($1::TEXT IS NULL OR shared_by = $1::TEXT) AND ($6::TEXT IS NULL OR shared_by IN (SELECT followee_id FROM follows WHERE follower_id = $6::TEXT)) AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT)) AND ($4::_TEXT IS NULL OR id IN (SELECT mt.movie_id FROM movie_tags mt JOIN tags t ON t.id = mt.tag_id WHERE t.name = ANY($4::_TEXT) GROUP BY mt.movie_id HAVING NOT $5::BOOLEAN OR COUNT(*) = cardinality($4::_TEXT))) AND deleted_at IS NULL AND hidden_at IS NULL ORDER BY created_at DESC, id DESC LIMIT 5 OFFSET 10")).
+					WithArgs(args.UserID, nil, nil, nil, false, nil).
 					WillReturnError(sql.ErrNoRows)
 			},
 		},
@@ -174,4 +179,287 @@ func TestMovieRepository_List(t *testing.T) {
 			assert.NotNil(t, movie)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestMovieRepository_List_Keyset(t *testing.T) {
+	db, mock := NewMock()
+	repo := MovieRepository{DB: db}
+
+	offset := 10
+	limit := 5
+	after := &cursor.Cursor{CreatedAt: time.Now(), ID: "last-id"}
+	args := &ListMoviesArgs{
+		After:  after,
+		Offset: &offset,
+		Limit:  &limit,
+	}
+
+	ctx := context.Background()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE ($1::TEXT IS NULL OR shared_by = $1::TEXT) AND ($6::TEXT IS NULL OR shared_by IN (SELECT followee_id FROM follows WHERE follower_id = $6::TEXT)) AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT)) AND ($4::_TEXT IS NULL OR id IN (SELECT mt.movie_id FROM movie_tags mt JOIN tags t ON t.id = mt.tag_id WHERE t.name = ANY($4::_TEXT) GROUP BY mt.movie_id HAVING NOT $5::BOOLEAN OR COUNT(*) = cardinality($4::_TEXT))) AND deleted_at IS NULL AND hidden_at IS NULL ORDER BY created_at DESC, id DESC LIMIT 5 OFFSET 0")).
+		WithArgs(args.UserID, &after.CreatedAt, &after.ID, nil, false, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "link", "thumbnail", "provider", "author", "duration", "shared_by", "shared_at", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "name", "description", "link", "thumbnail", "youtube", "author", 60, "1", time.Now(), time.Now(), time.Now(), nil))
+
+	movies, err := repo.List(ctx, args)
+	assert.NoError(t, err)
+	assert.Len(t, movies, 1)
+}
+
+func TestMovieRepository_List_Tags(t *testing.T) {
+	db, mock := NewMock()
+	repo := MovieRepository{DB: db}
+
+	limit := 5
+	args := &ListMoviesArgs{
+		Tags:         []string{"golang", "talks"},
+		MatchAllTags: true,
+		Limit:        &limit,
+	}
+
+	ctx := context.Background()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE ($1::TEXT IS NULL OR shared_by = $1::TEXT) AND ($6::TEXT IS NULL OR shared_by IN (SELECT followee_id FROM follows WHERE follower_id = $6::TEXT)) AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT)) AND ($4::_TEXT IS NULL OR id IN (SELECT mt.movie_id FROM movie_tags mt JOIN tags t ON t.id = mt.tag_id WHERE t.name = ANY($4::_TEXT) GROUP BY mt.movie_id HAVING NOT $5::BOOLEAN OR COUNT(*) = cardinality($4::_TEXT))) AND deleted_at IS NULL AND hidden_at IS NULL ORDER BY created_at DESC, id DESC LIMIT 5 OFFSET 0")).
+		WithArgs(nil, nil, nil, pq.StringArray(args.Tags), true, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "link", "thumbnail", "provider", "author", "duration", "shared_by", "shared_at", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "name", "description", "link", "thumbnail", "youtube", "author", 60, "1", time.Now(), time.Now(), time.Now(), nil))
+
+	movies, err := repo.List(ctx, args)
+	assert.NoError(t, err)
+	assert.Len(t, movies, 1)
+}
+
+func TestMovieRepository_List_FollowedBy(t *testing.T) {
+	db, mock := NewMock()
+	repo := MovieRepository{DB: db}
+
+	limit := 5
+	followerID := "follower-id"
+	args := &ListMoviesArgs{
+		FollowedBy: &followerID,
+		Limit:      &limit,
+	}
+
+	ctx := context.Background()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at FROM movies WHERE ($1::TEXT IS NULL OR shared_by = $1::TEXT) AND ($6::TEXT IS NULL OR shared_by IN (SELECT followee_id FROM follows WHERE follower_id = $6::TEXT)) AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::TEXT)) AND ($4::_TEXT IS NULL OR id IN (SELECT mt.movie_id FROM movie_tags mt JOIN tags t ON t.id = mt.tag_id WHERE t.name = ANY($4::_TEXT) GROUP BY mt.movie_id HAVING NOT $5::BOOLEAN OR COUNT(*) = cardinality($4::_TEXT))) AND deleted_at IS NULL AND hidden_at IS NULL ORDER BY created_at DESC, id DESC LIMIT 5 OFFSET 0")).
+		WithArgs(nil, nil, nil, nil, false, &followerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "link", "thumbnail", "provider", "author", "duration", "shared_by", "shared_at", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), "name", "description", "link", "thumbnail", "youtube", "author", 60, "followee-id", time.Now(), time.Now(), time.Now(), nil))
+
+	movies, err := repo.List(ctx, args)
+	assert.NoError(t, err)
+	assert.Len(t, movies, 1)
+}
+
+func TestMovieRepository_Update(t *testing.T) {
+	db, mock := NewMock()
+	repo := MovieRepository{DB: db}
+
+	now := time.Now()
+	m := &entities.Movie{
+		ID:          "id",
+		Name:        "new name",
+		Description: "new description",
+		SharedBy:    "user-id",
+		UpdatedAt:   &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         m,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE movies SET name = $1, description = $2, updated_at = $3 WHERE id = $4 AND shared_by = $5 AND deleted_at IS NULL")).
+					WithArgs(m.Name, m.Description, m.UpdatedAt, m.ID, m.SharedBy).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         m,
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE movies SET name = $1, description = $2, updated_at = $3 WHERE id = $4 AND shared_by = $5 AND deleted_at IS NULL")).
+					WithArgs(m.Name, m.Description, m.UpdatedAt, m.ID, m.SharedBy).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+		{
+			name:        "no row affected",
+			req:         m,
+			expectedErr: fmt.Errorf("can't update movie"),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE movies SET name = $1, description = $2, updated_at = $3 WHERE id = $4 AND shared_by = $5 AND deleted_at IS NULL")).
+					WithArgs(m.Name, m.Description, m.UpdatedAt, m.ID, m.SharedBy).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Update(ctx, testCase.req.(*entities.Movie))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestMovieRepository_SoftDelete(t *testing.T) {
+	db, mock := NewMock()
+	repo := MovieRepository{DB: db}
+
+	type Args struct {
+		ID        string
+		UserID    string
+		DeletedAt time.Time
+	}
+	args := &Args{
+		ID:        "id",
+		UserID:    "user-id",
+		DeletedAt: time.Now(),
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE movies SET deleted_at = $1, updated_at = $1 WHERE id = $2 AND shared_by = $3 AND deleted_at IS NULL")).
+					WithArgs(args.DeletedAt, args.ID, args.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "no row affected",
+			req:         args,
+			expectedErr: fmt.Errorf("can't delete movie"),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE movies SET deleted_at = $1, updated_at = $1 WHERE id = $2 AND shared_by = $3 AND deleted_at IS NULL")).
+					WithArgs(args.DeletedAt, args.ID, args.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		args := testCase.req.(*Args)
+		err := repo.SoftDelete(ctx, args.ID, args.UserID, args.DeletedAt)
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestMovieRepository_CountByUserID(t *testing.T) {
+	db, mock := NewMock()
+	repo := MovieRepository{DB: db}
+
+	testCases := []TestCase{
+		{
+			name:         "happy case",
+			req:          "user-id",
+			expectedResp: 3,
+			expectedErr:  nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies WHERE shared_by = $1 AND deleted_at IS NULL AND hidden_at IS NULL")).
+					WithArgs("user-id").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+		},
+		{
+			name:         "query error",
+			req:          "user-id",
+			expectedResp: 0,
+			expectedErr:  fmt.Errorf("row.Scan: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies WHERE shared_by = $1 AND deleted_at IS NULL AND hidden_at IS NULL")).
+					WithArgs("user-id").
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		count, err := repo.CountByUserID(ctx, testCase.req.(string))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+		assert.Equal(t, testCase.expectedResp, count)
+	}
+}
+
+func TestMovieRepository_Search(t *testing.T) {
+	db, mock := NewMock()
+	repo := MovieRepository{DB: db}
+
+	args := &SearchMoviesArgs{
+		Query:  "funny cats",
+		Offset: 0,
+		Limit:  10,
+	}
+	stmt := "SELECT id,name,description,link,thumbnail,provider,author,duration,shared_by,shared_at,created_at,updated_at,deleted_at, ts_rank(search_vector, query) AS rank, ts_headline('english', name, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'), ts_headline('english', description, query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') FROM movies, websearch_to_tsquery('english', $1) query WHERE search_vector @@ query AND deleted_at IS NULL AND hidden_at IS NULL ORDER BY rank DESC, created_at DESC, id DESC LIMIT $2 OFFSET $3"
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta(stmt)).
+					WithArgs(args.Query, args.Limit, args.Offset).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "link", "thumbnail", "provider", "author", "duration", "shared_by", "shared_at", "created_at", "updated_at", "deleted_at", "rank", "name_highlight", "description_highlight"}).AddRow(idutil.NewID(), "funny cats", "description", "link", "thumbnail", "youtube", "author", 60, "1", time.Now(), time.Now(), time.Now(), nil, 0.6, "<mark>funny</mark> <mark>cats</mark>", "description"))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         args,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta(stmt)).
+					WithArgs(args.Query, args.Limit, args.Offset).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		results, err := repo.Search(ctx, testCase.req.(*SearchMoviesArgs))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, "<mark>funny</mark> <mark>cats</mark>", results[0].NameHighlight)
+		}
+	}
+}
+
+func TestMovieRepository_SetHidden(t *testing.T) {
+	db, mock := NewMock()
+	repo := MovieRepository{DB: db}
+
+	now := time.Now()
+	moderatorID := "moderator-id"
+
+	ctx := context.Background()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE movies SET hidden_at = $1, hidden_by = $2, updated_at = $3 WHERE id = $4 AND deleted_at IS NULL")).
+		WithArgs(&now, &moderatorID, now, "id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetHidden(ctx, "id", &moderatorID, now))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE movies SET hidden_at = $1, hidden_by = $2, updated_at = $3 WHERE id = $4 AND deleted_at IS NULL")).
+		WithArgs(nil, nil, now, "unknown-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err := repo.SetHidden(ctx, "unknown-id", nil, now)
+	assert.EqualError(t, err, "can't hide movie: sql: no rows in result set")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
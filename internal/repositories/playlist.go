@@ -0,0 +1,275 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/golibs/database"
+
+	"github.com/lib/pq"
+)
+
+type PlaylistRepository struct {
+	*sql.DB
+}
+
+func NewPlaylistRepository(db *sql.DB) *PlaylistRepository {
+	return &PlaylistRepository{
+		db,
+	}
+}
+
+func (r *PlaylistRepository) Create(ctx context.Context, p *entities.Playlist) error {
+	fields, values := p.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)`, p.TableName(), strings.Join(fields, ","), placeHolders)
+	result, err := r.DB.ExecContext(ctx, stmt, values...)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't insert playlist")
+	}
+
+	return nil
+}
+
+// FindByID find playlist by id, whatever its visibility
+func (r *PlaylistRepository) FindByID(ctx context.Context, id string) (*entities.Playlist, error) {
+	playlist := &entities.Playlist{}
+	fields, values := playlist.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 AND deleted_at IS NULL`, strings.Join(fields, ","), playlist.TableName())
+	row := r.QueryRowContext(ctx, stmt, id)
+
+	if err := row.Scan(values...); err != nil {
+		return nil, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return playlist, nil
+}
+
+type ListPlaylistsArgs struct {
+	// UserID only lists the playlists of a user when it's set
+	UserID *string
+	// ViewerID also sees their private playlists, other private playlists are never listed
+	ViewerID string
+	// After is the last playlist of the previous page, nil for the first page
+	After *cursor.Cursor
+	Limit int
+}
+
+// List find playlists visible to the viewer, newest first
+func (r *PlaylistRepository) List(ctx context.Context, args *ListPlaylistsArgs) (ps entities.Playlists, _ error) {
+	playlist := &entities.Playlist{}
+	fields, _ := playlist.FieldMap()
+
+	var afterCreatedAt *time.Time
+	var afterID *string
+	if args.After != nil {
+		afterCreatedAt = &args.After.CreatedAt
+		afterID = &args.After.ID
+	}
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE ($1::TEXT IS NULL OR user_id = $1::TEXT) AND
+	(visibility = '%s' OR user_id = $2) AND
+	($3::TIMESTAMPTZ IS NULL OR (created_at, id) < ($3::TIMESTAMPTZ, $4::TEXT)) AND
+	deleted_at IS NULL
+	ORDER BY created_at DESC, id DESC
+	LIMIT $5`, strings.Join(fields, ","), playlist.TableName(), entities.PlaylistPublic)
+	rows, err := r.QueryContext(ctx, stmt, args.UserID, args.ViewerID, afterCreatedAt, afterID, args.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		p := &entities.Playlist{}
+		_, values := p.FieldMap()
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		ps = append(ps, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return ps, nil
+}
+
+// Update updates name, description and visibility of a playlist owned by the playlist's UserID
+func (r *PlaylistRepository) Update(ctx context.Context, p *entities.Playlist) error {
+	stmt := fmt.Sprintf(`UPDATE %s SET name = $1, description = $2, visibility = $3, updated_at = $4
+	WHERE id = $5 AND user_id = $6 AND deleted_at IS NULL`, p.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, p.Name, p.Description, p.Visibility, p.UpdatedAt, p.ID, p.UserID)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't update playlist")
+	}
+
+	return nil
+}
+
+// SoftDelete marks a playlist owned by userID as deleted
+func (r *PlaylistRepository) SoftDelete(ctx context.Context, id, userID string, deletedAt time.Time) error {
+	playlist := &entities.Playlist{}
+	stmt := fmt.Sprintf(`UPDATE %s SET deleted_at = $1, updated_at = $1
+	WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL`, playlist.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, deletedAt, id, userID)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't delete playlist")
+	}
+
+	return nil
+}
+
+type PlaylistItemRepository struct {
+	*sql.DB
+}
+
+func NewPlaylistItemRepository(db *sql.DB) *PlaylistItemRepository {
+	return &PlaylistItemRepository{
+		db,
+	}
+}
+
+// Add appends the movie to the playlist, it's a no-op when the movie is already in the playlist
+func (r *PlaylistItemRepository) Add(ctx context.Context, playlistID, movieID string, addedAt time.Time) error {
+	item := &entities.PlaylistItem{}
+	fields, _ := item.FieldMap()
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s)
+	SELECT $1::TEXT, $2::TEXT, COALESCE(MAX(position) + 1, 0), $3 FROM %s WHERE playlist_id = $1::TEXT
+	ON CONFLICT (playlist_id, movie_id) DO NOTHING`, item.TableName(), strings.Join(fields, ","), item.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, playlistID, movieID, addedAt); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// Remove removes the movie from the playlist, it's a no-op when the movie isn't in the playlist
+func (r *PlaylistItemRepository) Remove(ctx context.Context, playlistID, movieID string) error {
+	item := &entities.PlaylistItem{}
+	stmt := fmt.Sprintf(`DELETE FROM %s WHERE playlist_id = $1 AND movie_id = $2`, item.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, playlistID, movieID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// Reorder moves the items to the position of their movie in movieIDs, which must list every item of the playlist
+func (r *PlaylistItemRepository) Reorder(ctx context.Context, playlistID string, movieIDs []string) error {
+	item := &entities.PlaylistItem{}
+	stmt := fmt.Sprintf(`UPDATE %s SET position = t.position - 1
+	FROM unnest($2::_TEXT) WITH ORDINALITY AS t(movie_id, position)
+	WHERE %s.playlist_id = $1 AND %s.movie_id = t.movie_id`, item.TableName(), item.TableName(), item.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, playlistID, pq.StringArray(movieIDs))
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != int64(len(movieIDs)) {
+		return fmt.Errorf("can't reorder playlist items")
+	}
+
+	return nil
+}
+
+// ListByPlaylistID find the items of a playlist in play order
+func (r *PlaylistItemRepository) ListByPlaylistID(ctx context.Context, playlistID string) (is entities.PlaylistItems, _ error) {
+	item := &entities.PlaylistItem{}
+	fields, _ := item.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE playlist_id = $1
+	ORDER BY position ASC, added_at ASC`, strings.Join(fields, ","), item.TableName())
+	rows, err := r.QueryContext(ctx, stmt, playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		i := &entities.PlaylistItem{}
+		_, values := i.FieldMap()
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		is = append(is, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return is, nil
+}
+
+// CountByPlaylistIDs counts the visible movies of every playlist in a single query
+func (r *PlaylistItemRepository) CountByPlaylistIDs(ctx context.Context, playlistIDs []string) (map[string]int, error) {
+	item := &entities.PlaylistItem{}
+	movie := &entities.Movie{}
+	stmt := fmt.Sprintf(`SELECT i.playlist_id, COUNT(*)
+	FROM %s i JOIN %s m ON m.id = i.movie_id
+	WHERE i.playlist_id = ANY($1::_TEXT) AND m.deleted_at IS NULL AND m.hidden_at IS NULL
+	GROUP BY i.playlist_id`, item.TableName(), movie.TableName())
+	rows, err := r.QueryContext(ctx, stmt, pq.StringArray(playlistIDs))
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var playlistID string
+		var count int
+		if err := rows.Scan(&playlistID, &count); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		counts[playlistID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"remi/pkg/golibs/idutil"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaylistRepository_Create(t *testing.T) {
+	db, mock := NewMock()
+	repo := PlaylistRepository{DB: db}
+
+	now := time.Now()
+	p := &entities.Playlist{
+		ID:          idutil.NewID(),
+		UserID:      "user-id",
+		Name:        "Friday demos",
+		Description: "description",
+		Visibility:  entities.PlaylistPublic,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         p,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO playlists(id,user_id,name,description,visibility,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")).
+					WithArgs(p.ID, p.UserID, p.Name, p.Description, p.Visibility, p.CreatedAt, p.UpdatedAt, p.DeletedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "no row affected",
+			req:         p,
+			expectedErr: fmt.Errorf("can't insert playlist"),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO playlists(id,user_id,name,description,visibility,created_at,updated_at,deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")).
+					WithArgs(p.ID, p.UserID, p.Name, p.Description, p.Visibility, p.CreatedAt, p.UpdatedAt, p.DeletedAt).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Create(ctx, testCase.req.(*entities.Playlist))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestPlaylistRepository_List(t *testing.T) {
+	db, mock := NewMock()
+	repo := PlaylistRepository{DB: db}
+
+	userID := "user-id"
+	args := &ListPlaylistsArgs{
+		UserID:   &userID,
+		ViewerID: "viewer-id",
+		Limit:    10,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,user_id,name,description,visibility,created_at,updated_at,deleted_at FROM playlists WHERE ($1::TEXT IS NULL OR user_id = $1::TEXT) AND (visibility = 'public' OR user_id = $2) AND ($3::TIMESTAMPTZ IS NULL OR (created_at, id) < ($3::TIMESTAMPTZ, $4::TEXT)) AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $5")).
+					WithArgs(args.UserID, args.ViewerID, nil, nil, args.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "visibility", "created_at", "updated_at", "deleted_at"}).AddRow(idutil.NewID(), userID, "Friday demos", "", entities.PlaylistPublic, time.Now(), time.Now(), nil))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         args,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,user_id,name,description,visibility,created_at,updated_at,deleted_at FROM playlists WHERE ($1::TEXT IS NULL OR user_id = $1::TEXT) AND (visibility = 'public' OR user_id = $2) AND ($3::TIMESTAMPTZ IS NULL OR (created_at, id) < ($3::TIMESTAMPTZ, $4::TEXT)) AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $5")).
+					WithArgs(args.UserID, args.ViewerID, nil, nil, args.Limit).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		playlists, err := repo.List(ctx, testCase.req.(*ListPlaylistsArgs))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, "Friday demos", playlists[0].Name)
+		}
+	}
+}
+
+func TestPlaylistItemRepository_Add(t *testing.T) {
+	db, mock := NewMock()
+	repo := PlaylistItemRepository{DB: db}
+
+	now := time.Now()
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         "movie-id",
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO playlist_items(playlist_id,movie_id,position,added_at) SELECT $1::TEXT, $2::TEXT, COALESCE(MAX(position) + 1, 0), $3 FROM playlist_items WHERE playlist_id = $1::TEXT ON CONFLICT (playlist_id, movie_id) DO NOTHING")).
+					WithArgs("playlist-id", "movie-id", now).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         "movie-id",
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO playlist_items(playlist_id,movie_id,position,added_at) SELECT $1::TEXT, $2::TEXT, COALESCE(MAX(position) + 1, 0), $3 FROM playlist_items WHERE playlist_id = $1::TEXT ON CONFLICT (playlist_id, movie_id) DO NOTHING")).
+					WithArgs("playlist-id", "movie-id", now).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Add(ctx, "playlist-id", testCase.req.(string), now)
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestPlaylistItemRepository_Reorder(t *testing.T) {
+	db, mock := NewMock()
+	repo := PlaylistItemRepository{DB: db}
+
+	movieIDs := []string{"movie-2", "movie-1"}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         movieIDs,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE playlist_items SET position = t.position - 1 FROM unnest($2::_TEXT) WITH ORDINALITY AS t(movie_id, position) WHERE playlist_items.playlist_id = $1 AND playlist_items.movie_id = t.movie_id")).
+					WithArgs("playlist-id", pq.StringArray(movieIDs)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name:        "movie not in the playlist",
+			req:         movieIDs,
+			expectedErr: fmt.Errorf("can't reorder playlist items"),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE playlist_items SET position = t.position - 1 FROM unnest($2::_TEXT) WITH ORDINALITY AS t(movie_id, position) WHERE playlist_items.playlist_id = $1 AND playlist_items.movie_id = t.movie_id")).
+					WithArgs("playlist-id", pq.StringArray(movieIDs)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Reorder(ctx, "playlist-id", testCase.req.([]string))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
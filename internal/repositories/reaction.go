@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/database"
+
+	"github.com/lib/pq"
+)
+
+type ReactionRepository struct {
+	*sql.DB
+}
+
+func NewReactionRepository(db *sql.DB) *ReactionRepository {
+	return &ReactionRepository{
+		db,
+	}
+}
+
+// Upsert creates the reaction or replaces the previous vote of the user on the movie
+func (r *ReactionRepository) Upsert(ctx context.Context, e *entities.Reaction) error {
+	fields, values := e.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)
+	ON CONFLICT (movie_id, user_id) DO UPDATE SET type = EXCLUDED.type, updated_at = EXCLUDED.updated_at`, e.TableName(), strings.Join(fields, ","), placeHolders)
+	result, err := r.DB.ExecContext(ctx, stmt, values...)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't upsert reaction")
+	}
+
+	return nil
+}
+
+// Delete removes the vote of the user on the movie, it's a no-op when the user hasn't voted
+func (r *ReactionRepository) Delete(ctx context.Context, movieID, userID string) error {
+	reaction := &entities.Reaction{}
+	stmt := fmt.Sprintf(`DELETE FROM %s WHERE movie_id = $1 AND user_id = $2`, reaction.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, movieID, userID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// CountByMovieIDs counts likes and dislikes of every movie in a single query
+func (r *ReactionRepository) CountByMovieIDs(ctx context.Context, movieIDs []string) (map[string]*entities.ReactionCount, error) {
+	reaction := &entities.Reaction{}
+	stmt := fmt.Sprintf(`SELECT movie_id,
+	COUNT(*) FILTER (WHERE type = '%s'),
+	COUNT(*) FILTER (WHERE type = '%s')
+	FROM %s
+	WHERE movie_id = ANY($1::_TEXT)
+	GROUP BY movie_id`, entities.ReactionLike, entities.ReactionDislike, reaction.TableName())
+	rows, err := r.QueryContext(ctx, stmt, pq.StringArray(movieIDs))
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	counts := make(map[string]*entities.ReactionCount)
+	for rows.Next() {
+		c := &entities.ReactionCount{}
+		if err := rows.Scan(&c.MovieID, &c.Likes, &c.Dislikes); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		counts[c.MovieID] = c
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ListByUserAndMovieIDs returns the votes of the user on the movies keyed by movie id
+func (r *ReactionRepository) ListByUserAndMovieIDs(ctx context.Context, userID string, movieIDs []string) (map[string]*entities.Reaction, error) {
+	reaction := &entities.Reaction{}
+	fields, _ := reaction.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE user_id = $1 AND movie_id = ANY($2::_TEXT)`, strings.Join(fields, ","), reaction.TableName())
+	rows, err := r.QueryContext(ctx, stmt, userID, pq.StringArray(movieIDs))
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	reactions := make(map[string]*entities.Reaction)
+	for rows.Next() {
+		e := &entities.Reaction{}
+		_, values := e.FieldMap()
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		reactions[e.MovieID] = e
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return reactions, nil
+}
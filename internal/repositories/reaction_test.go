@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReactionRepository_Upsert(t *testing.T) {
+	db, mock := NewMock()
+	repo := ReactionRepository{DB: db}
+
+	now := time.Now()
+	e := &entities.Reaction{
+		MovieID:   "movie-id",
+		UserID:    "user-id",
+		Type:      entities.ReactionLike,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         e,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movie_reactions(movie_id,user_id,type,created_at,updated_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (movie_id, user_id) DO UPDATE SET type = EXCLUDED.type, updated_at = EXCLUDED.updated_at")).
+					WithArgs(e.MovieID, e.UserID, e.Type, e.CreatedAt, e.UpdatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         e,
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movie_reactions(movie_id,user_id,type,created_at,updated_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (movie_id, user_id) DO UPDATE SET type = EXCLUDED.type, updated_at = EXCLUDED.updated_at")).
+					WithArgs(e.MovieID, e.UserID, e.Type, e.CreatedAt, e.UpdatedAt).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Upsert(ctx, testCase.req.(*entities.Reaction))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestReactionRepository_CountByMovieIDs(t *testing.T) {
+	db, mock := NewMock()
+	repo := ReactionRepository{DB: db}
+
+	movieIDs := []string{"movie-1", "movie-2"}
+
+	testCases := []TestCase{
+		{
+			name: "happy case",
+			req:  movieIDs,
+			expectedResp: map[string]*entities.ReactionCount{
+				"movie-1": {MovieID: "movie-1", Likes: 3, Dislikes: 1},
+			},
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, COUNT(*) FILTER (WHERE type = 'like'), COUNT(*) FILTER (WHERE type = 'dislike') FROM movie_reactions WHERE movie_id = ANY($1::_TEXT) GROUP BY movie_id")).
+					WithArgs(pq.StringArray(movieIDs)).
+					WillReturnRows(sqlmock.NewRows([]string{"movie_id", "likes", "dislikes"}).AddRow("movie-1", 3, 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         movieIDs,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, COUNT(*) FILTER (WHERE type = 'like'), COUNT(*) FILTER (WHERE type = 'dislike') FROM movie_reactions WHERE movie_id = ANY($1::_TEXT) GROUP BY movie_id")).
+					WithArgs(pq.StringArray(movieIDs)).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		counts, err := repo.CountByMovieIDs(ctx, testCase.req.([]string))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedResp, counts)
+		}
+	}
+}
+
+func TestReactionRepository_ListByUserAndMovieIDs(t *testing.T) {
+	db, mock := NewMock()
+	repo := ReactionRepository{DB: db}
+
+	userID := "user-id"
+	movieIDs := []string{"movie-1", "movie-2"}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         movieIDs,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id,user_id,type,created_at,updated_at FROM movie_reactions WHERE user_id = $1 AND movie_id = ANY($2::_TEXT)")).
+					WithArgs(userID, pq.StringArray(movieIDs)).
+					WillReturnRows(sqlmock.NewRows([]string{"movie_id", "user_id", "type", "created_at", "updated_at"}).AddRow("movie-1", userID, entities.ReactionDislike, time.Now(), time.Now()))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         movieIDs,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id,user_id,type,created_at,updated_at FROM movie_reactions WHERE user_id = $1 AND movie_id = ANY($2::_TEXT)")).
+					WithArgs(userID, pq.StringArray(movieIDs)).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		reactions, err := repo.ListByUserAndMovieIDs(ctx, userID, testCase.req.([]string))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, entities.ReactionDislike, reactions["movie-1"].Type)
+		}
+	}
+}
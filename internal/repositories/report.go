@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/database"
+)
+
+type ReportRepository struct {
+	*sql.DB
+}
+
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{
+		db,
+	}
+}
+
+func (r *ReportRepository) Create(ctx context.Context, e *entities.Report) error {
+	fields, values := e.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)`, e.TableName(), strings.Join(fields, ","), placeHolders)
+	result, err := r.DB.ExecContext(ctx, stmt, values...)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't insert report")
+	}
+
+	return nil
+}
+
+type ListReportsArgs struct {
+	Offset int
+	Limit  int
+}
+
+// ListUnresolved find reports which weren't handled yet, newest first
+func (r *ReportRepository) ListUnresolved(ctx context.Context, args *ListReportsArgs) (rs entities.Reports, _ error) {
+	report := &entities.Report{}
+	fields, _ := report.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE resolved_at IS NULL
+	ORDER BY created_at DESC, id DESC
+	LIMIT $1
+	OFFSET $2`, strings.Join(fields, ","), report.TableName())
+	rows, err := r.QueryContext(ctx, stmt, args.Limit, args.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		e := &entities.Report{}
+		_, values := e.FieldMap()
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		rs = append(rs, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return rs, nil
+}
+
+// ResolveByMovieID resolves every pending report of the movie
+func (r *ReportRepository) ResolveByMovieID(ctx context.Context, movieID string, resolvedAt time.Time) error {
+	report := &entities.Report{}
+	stmt := fmt.Sprintf(`UPDATE %s SET resolved_at = $1 WHERE movie_id = $2 AND resolved_at IS NULL`, report.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, resolvedAt, movieID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
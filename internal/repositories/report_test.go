@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"remi/pkg/golibs/idutil"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRepository_Create(t *testing.T) {
+	db, mock := NewMock()
+	repo := ReportRepository{DB: db}
+
+	now := time.Now()
+	e := &entities.Report{
+		ID:        idutil.NewID(),
+		MovieID:   "movie-id",
+		UserID:    "user-id",
+		Reason:    "spam",
+		CreatedAt: &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         e,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movie_reports(id,movie_id,user_id,reason,created_at,resolved_at) VALUES ($1, $2, $3, $4, $5, $6)")).
+					WithArgs(e.ID, e.MovieID, e.UserID, e.Reason, e.CreatedAt, e.ResolvedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "no row affected",
+			req:         e,
+			expectedErr: fmt.Errorf("can't insert report"),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movie_reports(id,movie_id,user_id,reason,created_at,resolved_at) VALUES ($1, $2, $3, $4, $5, $6)")).
+					WithArgs(e.ID, e.MovieID, e.UserID, e.Reason, e.CreatedAt, e.ResolvedAt).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Create(ctx, testCase.req.(*entities.Report))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestReportRepository_ListUnresolved(t *testing.T) {
+	db, mock := NewMock()
+	repo := ReportRepository{DB: db}
+
+	args := &ListReportsArgs{
+		Offset: 0,
+		Limit:  10,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,movie_id,user_id,reason,created_at,resolved_at FROM movie_reports WHERE resolved_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2")).
+					WithArgs(args.Limit, args.Offset).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "movie_id", "user_id", "reason", "created_at", "resolved_at"}).AddRow(idutil.NewID(), "movie-id", "user-id", "spam", time.Now(), nil))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         args,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,movie_id,user_id,reason,created_at,resolved_at FROM movie_reports WHERE resolved_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2")).
+					WithArgs(args.Limit, args.Offset).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		reports, err := repo.ListUnresolved(ctx, testCase.req.(*ListReportsArgs))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Len(t, reports, 1)
+		}
+	}
+}
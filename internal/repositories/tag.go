@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/database"
+
+	"github.com/lib/pq"
+)
+
+type TagRepository struct {
+	database.DB
+}
+
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{
+		db,
+	}
+}
+
+// WithTx returns a copy of the repository running its queries in tx
+func (r *TagRepository) WithTx(tx *sql.Tx) *TagRepository {
+	return &TagRepository{
+		tx,
+	}
+}
+
+// AddToMovie tags the movie at now, creating the tags which don't exist yet in the same statement.
+// The ID of the tags which already exist is ignored.
+func (r *TagRepository) AddToMovie(ctx context.Context, movieID string, tags entities.Tags, now time.Time) error {
+	tag := &entities.Tag{}
+	movieTag := &entities.MovieTag{}
+
+	ids := make([]string, 0, len(tags))
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		ids = append(ids, t.ID)
+		names = append(names, t.Name)
+	}
+
+	// the no-op update makes RETURNING include the tags which already exist
+	stmt := fmt.Sprintf(`WITH t AS (
+		INSERT INTO %s(id,name,created_at)
+		SELECT id, name, $3 FROM unnest($1::_TEXT, $2::_TEXT) AS n(id, name)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id
+	)
+	INSERT INTO %s(movie_id,tag_id,created_at)
+	SELECT $4, id, $3 FROM t
+	ON CONFLICT (movie_id, tag_id) DO NOTHING`, tag.TableName(), movieTag.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, pq.StringArray(ids), pq.StringArray(names), now, movieID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// ListNamesByMovieIDs finds the tag names of every movie in a single query, sorted by name
+func (r *TagRepository) ListNamesByMovieIDs(ctx context.Context, movieIDs []string) (map[string][]string, error) {
+	tag := &entities.Tag{}
+	movieTag := &entities.MovieTag{}
+	stmt := fmt.Sprintf(`SELECT mt.movie_id, t.name
+	FROM %s mt JOIN %s t ON t.id = mt.tag_id
+	WHERE mt.movie_id = ANY($1::_TEXT)
+	ORDER BY t.name ASC`, movieTag.TableName(), tag.TableName())
+	rows, err := r.QueryContext(ctx, stmt, pq.StringArray(movieIDs))
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	names := make(map[string][]string)
+	for rows.Next() {
+		var movieID, name string
+		if err := rows.Scan(&movieID, &name); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		names[movieID] = append(names[movieID], name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return names, nil
+}
+
+// Count counts the visible movies of the most used tags, most used first
+func (r *TagRepository) Count(ctx context.Context, limit int) (cs []*entities.TagCount, _ error) {
+	tag := &entities.Tag{}
+	movieTag := &entities.MovieTag{}
+	movie := &entities.Movie{}
+	stmt := fmt.Sprintf(`SELECT t.name, COUNT(*)
+	FROM %s t
+	JOIN %s mt ON mt.tag_id = t.id
+	JOIN %s m ON m.id = mt.movie_id
+	WHERE m.deleted_at IS NULL AND m.hidden_at IS NULL
+	GROUP BY t.name
+	ORDER BY COUNT(*) DESC, t.name ASC
+	LIMIT $1`, tag.TableName(), movieTag.TableName(), movie.TableName())
+	rows, err := r.QueryContext(ctx, stmt, limit)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		c := &entities.TagCount{}
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		cs = append(cs, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return cs, nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagRepository_AddToMovie(t *testing.T) {
+	db, mock := NewMock()
+	repo := TagRepository{DB: db}
+
+	now := time.Now()
+	tags := entities.Tags{
+		{ID: "tag-1", Name: "golang"},
+		{ID: "tag-2", Name: "talks"},
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         tags,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("WITH t AS ( INSERT INTO tags(id,name,created_at) SELECT id, name, $3 FROM unnest($1::_TEXT, $2::_TEXT) AS n(id, name) ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id ) INSERT INTO movie_tags(movie_id,tag_id,created_at) SELECT $4, id, $3 FROM t ON CONFLICT (movie_id, tag_id) DO NOTHING")).
+					WithArgs(pq.StringArray{"tag-1", "tag-2"}, pq.StringArray{"golang", "talks"}, now, "movie-id").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         tags,
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("WITH t AS ( INSERT INTO tags(id,name,created_at) SELECT id, name, $3 FROM unnest($1::_TEXT, $2::_TEXT) AS n(id, name) ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id ) INSERT INTO movie_tags(movie_id,tag_id,created_at) SELECT $4, id, $3 FROM t ON CONFLICT (movie_id, tag_id) DO NOTHING")).
+					WithArgs(pq.StringArray{"tag-1", "tag-2"}, pq.StringArray{"golang", "talks"}, now, "movie-id").
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.AddToMovie(ctx, "movie-id", testCase.req.(entities.Tags), now)
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestTagRepository_Count(t *testing.T) {
+	db, mock := NewMock()
+	repo := TagRepository{DB: db}
+
+	testCases := []TestCase{
+		{
+			name:         "happy case",
+			req:          10,
+			expectedResp: []*entities.TagCount{{Name: "golang", Count: 3}, {Name: "talks", Count: 1}},
+			expectedErr:  nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT t.name, COUNT(*) FROM tags t JOIN movie_tags mt ON mt.tag_id = t.id JOIN movies m ON m.id = mt.movie_id WHERE m.deleted_at IS NULL AND m.hidden_at IS NULL GROUP BY t.name ORDER BY COUNT(*) DESC, t.name ASC LIMIT $1")).
+					WithArgs(10).
+					WillReturnRows(sqlmock.NewRows([]string{"name", "count"}).AddRow("golang", 3).AddRow("talks", 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         10,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT t.name, COUNT(*) FROM tags t JOIN movie_tags mt ON mt.tag_id = t.id JOIN movies m ON m.id = mt.movie_id WHERE m.deleted_at IS NULL AND m.hidden_at IS NULL GROUP BY t.name ORDER BY COUNT(*) DESC, t.name ASC LIMIT $1")).
+					WithArgs(10).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		counts, err := repo.Count(ctx, testCase.req.(int))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedResp, counts)
+		}
+	}
+}
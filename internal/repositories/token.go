@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/database"
+)
+
+type RefreshTokenRepository struct {
+	database.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db,
+	}
+}
+
+// WithTx returns a copy of the repository running its queries in tx
+func (r *RefreshTokenRepository) WithTx(tx *sql.Tx) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		tx,
+	}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, t *entities.RefreshToken) error {
+	fields, values := t.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)`, t.TableName(), strings.Join(fields, ","), placeHolders)
+	result, err := r.DB.ExecContext(ctx, stmt, values...)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't insert refresh token")
+	}
+
+	return nil
+}
+
+// FindByHash find refresh token by the hash of the token, revoked tokens included
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	token := &entities.RefreshToken{}
+	fields, values := token.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE token_hash = $1`, strings.Join(fields, ","), token.TableName())
+	row := r.QueryRowContext(ctx, stmt, tokenHash)
+
+	if err := row.Scan(values...); err != nil {
+		return nil, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return token, nil
+}
+
+// Rotate revokes the token in favor of replacedBy. It fails with sql.ErrNoRows when the token was
+// already revoked so a token can't be rotated twice by concurrent requests.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, id, replacedBy string, revokedAt time.Time) error {
+	token := &entities.RefreshToken{}
+	stmt := fmt.Sprintf(`UPDATE %s SET revoked_at = $1, replaced_by = $2
+	WHERE id = $3 AND revoked_at IS NULL`, token.TableName())
+	result, err := r.DB.ExecContext(ctx, stmt, revokedAt, replacedBy, id)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't rotate refresh token: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every active token of the family
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	token := &entities.RefreshToken{}
+	stmt := fmt.Sprintf(`UPDATE %s SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`, token.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, revokedAt, familyID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeByUserID revokes every active token of the user
+func (r *RefreshTokenRepository) RevokeByUserID(ctx context.Context, userID string, revokedAt time.Time) error {
+	token := &entities.RefreshToken{}
+	stmt := fmt.Sprintf(`UPDATE %s SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`, token.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, revokedAt, userID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+type RevokedTokenRepository struct {
+	database.DB
+}
+
+func NewRevokedTokenRepository(db *sql.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		db,
+	}
+}
+
+// WithTx returns a copy of the repository running its queries in tx
+func (r *RevokedTokenRepository) WithTx(tx *sql.Tx) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		tx,
+	}
+}
+
+// Create revokes an access token, revoking it twice is a no-op
+func (r *RevokedTokenRepository) Create(ctx context.Context, t *entities.RevokedToken) error {
+	fields, values := t.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s) ON CONFLICT (jti) DO NOTHING`, t.TableName(), strings.Join(fields, ","), placeHolders)
+	if _, err := r.DB.ExecContext(ctx, stmt, values...); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// IsRevoked checks if the access token with the jti was revoked
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	token := &entities.RevokedToken{}
+	stmt := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE jti = $1)`, token.TableName())
+
+	var revoked bool
+	if err := r.QueryRowContext(ctx, stmt, jti).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"remi/pkg/golibs/idutil"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenRepository_Create(t *testing.T) {
+	db, mock := NewMock()
+	repo := RefreshTokenRepository{DB: db}
+
+	now := time.Now()
+	token := &entities.RefreshToken{
+		ID:        idutil.NewID(),
+		UserID:    "user-id",
+		TokenHash: "hash",
+		FamilyID:  "family-id",
+		ExpiresAt: &now,
+		CreatedAt: &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         token,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO refresh_tokens(id,user_id,token_hash,family_id,expires_at,revoked_at,replaced_by,created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")).
+					WithArgs(token.ID, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt, token.RevokedAt, token.ReplacedBy, token.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         token,
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO refresh_tokens(id,user_id,token_hash,family_id,expires_at,revoked_at,replaced_by,created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")).
+					WithArgs(token.ID, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt, token.RevokedAt, token.ReplacedBy, token.CreatedAt).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Create(ctx, testCase.req.(*entities.RefreshToken))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestRefreshTokenRepository_Rotate(t *testing.T) {
+	db, mock := NewMock()
+	repo := RefreshTokenRepository{DB: db}
+
+	type Args struct {
+		ID         string
+		ReplacedBy string
+		RevokedAt  time.Time
+	}
+	args := &Args{
+		ID:         "id",
+		ReplacedBy: "new-id",
+		RevokedAt:  time.Now(),
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked_at = $1, replaced_by = $2 WHERE id = $3 AND revoked_at IS NULL")).
+					WithArgs(args.RevokedAt, args.ReplacedBy, args.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "already rotated",
+			req:         args,
+			expectedErr: fmt.Errorf("can't rotate refresh token: %w", sql.ErrNoRows),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked_at = $1, replaced_by = $2 WHERE id = $3 AND revoked_at IS NULL")).
+					WithArgs(args.RevokedAt, args.ReplacedBy, args.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		args := testCase.req.(*Args)
+		err := repo.Rotate(ctx, args.ID, args.ReplacedBy, args.RevokedAt)
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestRevokedTokenRepository_IsRevoked(t *testing.T) {
+	db, mock := NewMock()
+	repo := RevokedTokenRepository{DB: db}
+
+	jti := "jti"
+	testCases := []TestCase{
+		{
+			name:         "revoked",
+			req:          jti,
+			expectedResp: true,
+			expectedErr:  nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)")).
+					WithArgs(jti).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         jti,
+			expectedErr: fmt.Errorf("row.Scan: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)")).
+					WithArgs(jti).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		revoked, err := repo.IsRevoked(ctx, testCase.req.(string))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedResp, revoked)
+		}
+	}
+}
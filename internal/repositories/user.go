@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"remi/internal/entities"
 	"remi/pkg/golibs/database"
@@ -13,7 +14,7 @@ import (
 )
 
 type UserRepository struct {
-	*sql.DB
+	database.DB
 }
 
 func NewUserRepository(db *sql.DB) *UserRepository {
@@ -22,6 +23,13 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	}
 }
 
+// WithTx returns a copy of the repository running its queries in tx
+func (r *UserRepository) WithTx(tx *sql.Tx) *UserRepository {
+	return &UserRepository{
+		tx,
+	}
+}
+
 func (r *UserRepository) Create(ctx context.Context, u *entities.User) error {
 	fields, values := u.FieldMap()
 	placeHolders := database.GeneratePlaceholders(len(fields))
@@ -107,3 +115,100 @@ func (r *UserRepository) List(ctx context.Context, args *ListUsersArgs) (us enti
 
 	return us, nil
 }
+
+type ListAllUsersArgs struct {
+	Offset int
+	Limit  int
+}
+
+// ListAll find users, oldest first
+func (r *UserRepository) ListAll(ctx context.Context, args *ListAllUsersArgs) (us entities.Users, _ error) {
+	user := &entities.User{}
+	fields, _ := user.FieldMap()
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	ORDER BY created_at ASC, id ASC
+	LIMIT $1
+	OFFSET $2`, strings.Join(fields, ","), user.TableName())
+	rows, err := r.QueryContext(ctx, stmt, args.Limit, args.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		u := &entities.User{}
+		_, values := u.FieldMap()
+		err := rows.Scan(values...)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		us = append(us, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return us, nil
+}
+
+// UpdateRole changes the role of the user
+func (r *UserRepository) UpdateRole(ctx context.Context, id, role string, updatedAt time.Time) error {
+	user := &entities.User{}
+	stmt := fmt.Sprintf(`UPDATE %s SET role = $1, updated_at = $2 WHERE id = $3`, user.TableName())
+	return r.updateOne(ctx, stmt, role, updatedAt, id)
+}
+
+// SetDisabled bans the user when disabledAt is set, or lifts the ban when it's nil
+func (r *UserRepository) SetDisabled(ctx context.Context, id string, disabledAt *time.Time, updatedAt time.Time) error {
+	user := &entities.User{}
+	stmt := fmt.Sprintf(`UPDATE %s SET disabled_at = $1, updated_at = $2 WHERE id = $3`, user.TableName())
+	return r.updateOne(ctx, stmt, disabledAt, updatedAt, id)
+}
+
+func (r *UserRepository) UpdateName(ctx context.Context, id, name string, updatedAt time.Time) error {
+	user := &entities.User{}
+	stmt := fmt.Sprintf(`UPDATE %s SET name = $1, updated_at = $2 WHERE id = $3`, user.TableName())
+	return r.updateOne(ctx, stmt, name, updatedAt, id)
+}
+
+// SetAvatar replaces the avatar key of the user, a nil key removes the avatar
+func (r *UserRepository) SetAvatar(ctx context.Context, id string, avatarKey *string, updatedAt time.Time) error {
+	user := &entities.User{}
+	stmt := fmt.Sprintf(`UPDATE %s SET avatar_key = $1, updated_at = $2 WHERE id = $3`, user.TableName())
+	return r.updateOne(ctx, stmt, avatarKey, updatedAt, id)
+}
+
+// UpdatePassword replaces the password hash of the user when it's still oldHash,
+// so that a hash computed from a stale password never overwrites a newer one
+func (r *UserRepository) UpdatePassword(ctx context.Context, id, oldHash, newHash string, updatedAt time.Time) error {
+	user := &entities.User{}
+	stmt := fmt.Sprintf(`UPDATE %s SET password = $1, updated_at = $2 WHERE id = $3 AND password = $4`, user.TableName())
+	return r.updateOne(ctx, stmt, newHash, updatedAt, id, oldHash)
+}
+
+// InvalidateTokens rejects the access tokens of the user issued before validAfter
+func (r *UserRepository) InvalidateTokens(ctx context.Context, id string, validAfter time.Time) error {
+	user := &entities.User{}
+	stmt := fmt.Sprintf(`UPDATE %s SET tokens_valid_after = $1 WHERE id = $2`, user.TableName())
+	return r.updateOne(ctx, stmt, validAfter, id)
+}
+
+func (r *UserRepository) updateOne(ctx context.Context, stmt string, args ...interface{}) error {
+	result, err := r.DB.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't update user")
+	}
+
+	return nil
+}
@@ -25,6 +25,7 @@ func TestUserRepository_Create(t *testing.T) {
 		Name:      "name",
 		Username:  "username",
 		Password:  "password",
+		Role:      entities.RoleUser,
 		CreatedAt: &now,
 		UpdatedAt: &now,
 	}
@@ -35,8 +36,8 @@ func TestUserRepository_Create(t *testing.T) {
 			req:         u,
 			expectedErr: nil,
 			setup: func(ctx context.Context) {
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users(id,username,password,name,created_at,updated_at) VALUES ($1, $2, $3, $4, $5, $6)")).
-					WithArgs(u.ID, u.Username, u.Password, u.Name, u.CreatedAt, u.UpdatedAt).
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users(id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)")).
+					WithArgs(u.ID, u.Username, u.Password, u.Name, u.Role, u.DisabledAt, u.AvatarKey, u.TokensValidAfter, u.CreatedAt, u.UpdatedAt).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
@@ -45,8 +46,8 @@ func TestUserRepository_Create(t *testing.T) {
 			req:         u,
 			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrNoRows),
 			setup: func(ctx context.Context) {
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users(id,username,password,name,created_at,updated_at) VALUES ($1, $2, $3, $4, $5, $6)")).
-					WithArgs(u.ID, u.Username, u.Password, u.Name, u.CreatedAt, u.UpdatedAt).
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users(id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)")).
+					WithArgs(u.ID, u.Username, u.Password, u.Name, u.Role, u.DisabledAt, u.AvatarKey, u.TokensValidAfter, u.CreatedAt, u.UpdatedAt).
 					WillReturnError(sql.ErrNoRows)
 			},
 		},
@@ -55,8 +56,8 @@ func TestUserRepository_Create(t *testing.T) {
 			req:         u,
 			expectedErr: fmt.Errorf("can't insert user"),
 			setup: func(ctx context.Context) {
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users(id,username,password,name,created_at,updated_at) VALUES ($1, $2, $3, $4, $5, $6)")).
-					WithArgs(u.ID, u.Username, u.Password, u.Name, u.CreatedAt, u.UpdatedAt).
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users(id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)")).
+					WithArgs(u.ID, u.Username, u.Password, u.Name, u.Role, u.DisabledAt, u.AvatarKey, u.TokensValidAfter, u.CreatedAt, u.UpdatedAt).
 					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 		},
@@ -85,9 +86,9 @@ func TestUserRepository_FindByUsername(t *testing.T) {
 			req:         arg,
 			expectedErr: nil,
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,created_at,updated_at FROM users WHERE username = $1")).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at FROM users WHERE username = $1")).
 					WithArgs(arg).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "name", "created_at", "updated_at"}).AddRow(idutil.NewID(), "username", "password", "name", time.Now(), time.Now()))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "name", "role", "disabled_at", "avatar_key", "tokens_valid_after", "created_at", "updated_at"}).AddRow(idutil.NewID(), "username", "password", "name", "user", nil, nil, nil, time.Now(), time.Now()))
 			},
 		},
 		{
@@ -95,7 +96,7 @@ func TestUserRepository_FindByUsername(t *testing.T) {
 			req:         arg,
 			expectedErr: sql.ErrNoRows,
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,created_at,updated_at FROM users WHERE username = $1")).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at FROM users WHERE username = $1")).
 					WithArgs(arg).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -126,9 +127,9 @@ func TestUserRepository_FindByID(t *testing.T) {
 			req:         arg,
 			expectedErr: nil,
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,created_at,updated_at FROM users WHERE id = $1")).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at FROM users WHERE id = $1")).
 					WithArgs(arg).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "name", "created_at", "updated_at"}).AddRow(idutil.NewID(), "username", "password", "name", time.Now(), time.Now()))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "name", "role", "disabled_at", "avatar_key", "tokens_valid_after", "created_at", "updated_at"}).AddRow(idutil.NewID(), "username", "password", "name", "user", nil, nil, nil, time.Now(), time.Now()))
 			},
 		},
 		{
@@ -136,7 +137,7 @@ func TestUserRepository_FindByID(t *testing.T) {
 			req:         arg,
 			expectedErr: sql.ErrNoRows,
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,created_at,updated_at FROM users WHERE id = $1")).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at FROM users WHERE id = $1")).
 					WithArgs(arg).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -170,9 +171,9 @@ func TestUserRepository_List(t *testing.T) {
 			req:         args,
 			expectedErr: nil,
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,created_at,updated_at FROM users WHERE id = ANY($1::_TEXT)")).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at FROM users WHERE id = ANY($1::_TEXT)")).
 					WithArgs(pq.StringArray(args.IDs)).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "name", "created_at", "updated_at"}).AddRow(idutil.NewID(), "username", "password", "name", time.Now(), time.Now()))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "name", "role", "disabled_at", "avatar_key", "tokens_valid_after", "created_at", "updated_at"}).AddRow(idutil.NewID(), "username", "password", "name", "user", nil, nil, nil, time.Now(), time.Now()))
 			},
 		},
 		{
@@ -180,7 +181,7 @@ func TestUserRepository_List(t *testing.T) {
 			req:         args,
 			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrNoRows),
 			setup: func(ctx context.Context) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,created_at,updated_at FROM users WHERE id = ANY($1::_TEXT)")).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at FROM users WHERE id = ANY($1::_TEXT)")).
 					WithArgs(pq.StringArray(args.IDs)).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -199,3 +200,139 @@ func TestUserRepository_List(t *testing.T) {
 		}
 	}
 }
+
+func TestUserRepository_ListAll(t *testing.T) {
+	db, mock := NewMock()
+	repo := UserRepository{DB: db}
+
+	args := &ListAllUsersArgs{
+		Offset: 20,
+		Limit:  10,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at FROM users ORDER BY created_at ASC, id ASC LIMIT $1 OFFSET $2")).
+					WithArgs(args.Limit, args.Offset).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "name", "role", "disabled_at", "avatar_key", "tokens_valid_after", "created_at", "updated_at"}).AddRow(idutil.NewID(), "username", "password", "name", "admin", nil, nil, nil, time.Now(), time.Now()))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         args,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id,username,password,name,role,disabled_at,avatar_key,tokens_valid_after,created_at,updated_at FROM users ORDER BY created_at ASC, id ASC LIMIT $1 OFFSET $2")).
+					WithArgs(args.Limit, args.Offset).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		users, err := repo.ListAll(ctx, testCase.req.(*ListAllUsersArgs))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, "admin", users[0].Role)
+		}
+	}
+}
+
+func TestUserRepository_UpdateRole(t *testing.T) {
+	db, mock := NewMock()
+	repo := UserRepository{DB: db}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET role = $1, updated_at = $2 WHERE id = $3")).
+		WithArgs(entities.RoleModerator, now, "id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateRole(ctx, "id", entities.RoleModerator, now))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET role = $1, updated_at = $2 WHERE id = $3")).
+		WithArgs(entities.RoleModerator, now, "unknown-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.EqualError(t, repo.UpdateRole(ctx, "unknown-id", entities.RoleModerator, now), "can't update user")
+}
+
+func TestUserRepository_SetDisabled(t *testing.T) {
+	db, mock := NewMock()
+	repo := UserRepository{DB: db}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET disabled_at = $1, updated_at = $2 WHERE id = $3")).
+		WithArgs(&now, now, "id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetDisabled(ctx, "id", &now, now))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET disabled_at = $1, updated_at = $2 WHERE id = $3")).
+		WithArgs(nil, now, "id").
+		WillReturnError(sql.ErrConnDone)
+	assert.EqualError(t, repo.SetDisabled(ctx, "id", nil, now), fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone).Error())
+}
+
+func TestUserRepository_UpdatePassword(t *testing.T) {
+	db, mock := NewMock()
+	repo := UserRepository{DB: db}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password = $1, updated_at = $2 WHERE id = $3 AND password = $4")).
+		WithArgs("new-hash", now, "id", "old-hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdatePassword(ctx, "id", "old-hash", "new-hash", now))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password = $1, updated_at = $2 WHERE id = $3 AND password = $4")).
+		WithArgs("new-hash", now, "id", "changed-hash").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.EqualError(t, repo.UpdatePassword(ctx, "id", "changed-hash", "new-hash", now), "can't update user")
+}
+
+func TestUserRepository_SetAvatar(t *testing.T) {
+	db, mock := NewMock()
+	repo := UserRepository{DB: db}
+
+	now := time.Now()
+	ctx := context.Background()
+	key := "avatar.png"
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET avatar_key = $1, updated_at = $2 WHERE id = $3")).
+		WithArgs(&key, now, "id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetAvatar(ctx, "id", &key, now))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET avatar_key = $1, updated_at = $2 WHERE id = $3")).
+		WithArgs(nil, now, "unknown-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.EqualError(t, repo.SetAvatar(ctx, "unknown-id", nil, now), "can't update user")
+}
+
+func TestUserRepository_InvalidateTokens(t *testing.T) {
+	db, mock := NewMock()
+	repo := UserRepository{DB: db}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET tokens_valid_after = $1 WHERE id = $2")).
+		WithArgs(now, "id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.InvalidateTokens(ctx, "id", now))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET tokens_valid_after = $1 WHERE id = $2")).
+		WithArgs(now, "unknown-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.EqualError(t, repo.InvalidateTokens(ctx, "unknown-id", now), "can't update user")
+}
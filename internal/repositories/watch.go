@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/golibs/database"
+
+	"github.com/lib/pq"
+)
+
+type WatchLaterRepository struct {
+	*sql.DB
+}
+
+func NewWatchLaterRepository(db *sql.DB) *WatchLaterRepository {
+	return &WatchLaterRepository{
+		db,
+	}
+}
+
+// Add saves the movie to watch later, it's a no-op when it's already saved
+func (r *WatchLaterRepository) Add(ctx context.Context, e *entities.WatchLaterItem) error {
+	fields, values := e.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)
+	ON CONFLICT (user_id, movie_id) DO NOTHING`, e.TableName(), strings.Join(fields, ","), placeHolders)
+	if _, err := r.DB.ExecContext(ctx, stmt, values...); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// Remove removes the movie from the list, it's a no-op when it isn't saved
+func (r *WatchLaterRepository) Remove(ctx context.Context, userID, movieID string) error {
+	item := &entities.WatchLaterItem{}
+	stmt := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1 AND movie_id = $2`, item.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, userID, movieID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+type ListWatchItemsArgs struct {
+	UserID string
+	// After is the last item of the previous page, its ID is the movie id
+	After *cursor.Cursor
+	Limit int
+}
+
+// List find the movies saved by the user, latest first
+func (r *WatchLaterRepository) List(ctx context.Context, args *ListWatchItemsArgs) (items entities.WatchLaterItems, _ error) {
+	item := &entities.WatchLaterItem{}
+	fields, _ := item.FieldMap()
+
+	var afterAddedAt *time.Time
+	var afterID *string
+	if args.After != nil {
+		afterAddedAt = &args.After.CreatedAt
+		afterID = &args.After.ID
+	}
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE user_id = $1 AND
+	($2::TIMESTAMPTZ IS NULL OR (added_at, movie_id) < ($2::TIMESTAMPTZ, $3::TEXT))
+	ORDER BY added_at DESC, movie_id DESC
+	LIMIT $4`, strings.Join(fields, ","), item.TableName())
+	rows, err := r.QueryContext(ctx, stmt, args.UserID, afterAddedAt, afterID, args.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		e := &entities.WatchLaterItem{}
+		_, values := e.FieldMap()
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		items = append(items, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return items, nil
+}
+
+type WatchHistoryRepository struct {
+	*sql.DB
+}
+
+func NewWatchHistoryRepository(db *sql.DB) *WatchHistoryRepository {
+	return &WatchHistoryRepository{
+		db,
+	}
+}
+
+// Record records that the user viewed the movie, viewing it again only moves it to the top of the history
+func (r *WatchHistoryRepository) Record(ctx context.Context, e *entities.WatchHistoryItem) error {
+	fields, values := e.FieldMap()
+	placeHolders := database.GeneratePlaceholders(len(fields))
+
+	stmt := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (%s)
+	ON CONFLICT (user_id, movie_id) DO UPDATE SET watched_at = EXCLUDED.watched_at`, e.TableName(), strings.Join(fields, ","), placeHolders)
+	result, err := r.DB.ExecContext(ctx, stmt, values...)
+	if err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	if rowAffected != 1 {
+		return fmt.Errorf("can't record watch")
+	}
+
+	return nil
+}
+
+// Remove removes the movie from the history, it's a no-op when it isn't in it
+func (r *WatchHistoryRepository) Remove(ctx context.Context, userID, movieID string) error {
+	item := &entities.WatchHistoryItem{}
+	stmt := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1 AND movie_id = $2`, item.TableName())
+	if _, err := r.DB.ExecContext(ctx, stmt, userID, movieID); err != nil {
+		return fmt.Errorf("r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// List find the movies viewed by the user, last viewed first
+func (r *WatchHistoryRepository) List(ctx context.Context, args *ListWatchItemsArgs) (items entities.WatchHistoryItems, _ error) {
+	item := &entities.WatchHistoryItem{}
+	fields, _ := item.FieldMap()
+
+	var afterWatchedAt *time.Time
+	var afterID *string
+	if args.After != nil {
+		afterWatchedAt = &args.After.CreatedAt
+		afterID = &args.After.ID
+	}
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE user_id = $1 AND
+	($2::TIMESTAMPTZ IS NULL OR (watched_at, movie_id) < ($2::TIMESTAMPTZ, $3::TEXT))
+	ORDER BY watched_at DESC, movie_id DESC
+	LIMIT $4`, strings.Join(fields, ","), item.TableName())
+	rows, err := r.QueryContext(ctx, stmt, args.UserID, afterWatchedAt, afterID, args.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		e := &entities.WatchHistoryItem{}
+		_, values := e.FieldMap()
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		items = append(items, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return items, nil
+}
+
+// ListWatchedMovieIDs returns which of the movies the user viewed
+func (r *WatchHistoryRepository) ListWatchedMovieIDs(ctx context.Context, userID string, movieIDs []string) (map[string]bool, error) {
+	item := &entities.WatchHistoryItem{}
+	stmt := fmt.Sprintf(`SELECT movie_id FROM %s
+	WHERE user_id = $1 AND movie_id = ANY($2::_TEXT)`, item.TableName())
+	rows, err := r.QueryContext(ctx, stmt, userID, pq.StringArray(movieIDs))
+	if err != nil {
+		return nil, fmt.Errorf("r.QueryContext: %w", err)
+	}
+
+	defer rows.Close()
+	watched := make(map[string]bool)
+	for rows.Next() {
+		var movieID string
+		if err := rows.Scan(&movieID); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		watched[movieID] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return watched, nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"remi/internal/entities"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchLaterRepository_List(t *testing.T) {
+	db, mock := NewMock()
+	repo := WatchLaterRepository{DB: db}
+
+	args := &ListWatchItemsArgs{
+		UserID: "user-id",
+		Limit:  10,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         args,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id,movie_id,added_at FROM watch_later WHERE user_id = $1 AND ($2::TIMESTAMPTZ IS NULL OR (added_at, movie_id) < ($2::TIMESTAMPTZ, $3::TEXT)) ORDER BY added_at DESC, movie_id DESC LIMIT $4")).
+					WithArgs(args.UserID, nil, nil, args.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "movie_id", "added_at"}).AddRow("user-id", "movie-id", time.Now()))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         args,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id,movie_id,added_at FROM watch_later WHERE user_id = $1 AND ($2::TIMESTAMPTZ IS NULL OR (added_at, movie_id) < ($2::TIMESTAMPTZ, $3::TEXT)) ORDER BY added_at DESC, movie_id DESC LIMIT $4")).
+					WithArgs(args.UserID, nil, nil, args.Limit).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		items, err := repo.List(ctx, testCase.req.(*ListWatchItemsArgs))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, "movie-id", items[0].MovieID)
+		}
+	}
+}
+
+func TestWatchHistoryRepository_Record(t *testing.T) {
+	db, mock := NewMock()
+	repo := WatchHistoryRepository{DB: db}
+
+	now := time.Now()
+	e := &entities.WatchHistoryItem{
+		UserID:    "user-id",
+		MovieID:   "movie-id",
+		WatchedAt: &now,
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "happy case",
+			req:         e,
+			expectedErr: nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO watch_history(user_id,movie_id,watched_at) VALUES ($1, $2, $3) ON CONFLICT (user_id, movie_id) DO UPDATE SET watched_at = EXCLUDED.watched_at")).
+					WithArgs(e.UserID, e.MovieID, e.WatchedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:        "exec error",
+			req:         e,
+			expectedErr: fmt.Errorf("r.DB.ExecContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO watch_history(user_id,movie_id,watched_at) VALUES ($1, $2, $3) ON CONFLICT (user_id, movie_id) DO UPDATE SET watched_at = EXCLUDED.watched_at")).
+					WithArgs(e.UserID, e.MovieID, e.WatchedAt).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		err := repo.Record(ctx, testCase.req.(*entities.WatchHistoryItem))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestWatchHistoryRepository_ListWatchedMovieIDs(t *testing.T) {
+	db, mock := NewMock()
+	repo := WatchHistoryRepository{DB: db}
+
+	movieIDs := []string{"movie-1", "movie-2"}
+
+	testCases := []TestCase{
+		{
+			name:         "happy case",
+			req:          movieIDs,
+			expectedResp: map[string]bool{"movie-1": true},
+			expectedErr:  nil,
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id FROM watch_history WHERE user_id = $1 AND movie_id = ANY($2::_TEXT)")).
+					WithArgs("user-id", pq.StringArray(movieIDs)).
+					WillReturnRows(sqlmock.NewRows([]string{"movie_id"}).AddRow("movie-1"))
+			},
+		},
+		{
+			name:        "query error",
+			req:         movieIDs,
+			expectedErr: fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone),
+			setup: func(ctx context.Context) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id FROM watch_history WHERE user_id = $1 AND movie_id = ANY($2::_TEXT)")).
+					WithArgs("user-id", pq.StringArray(movieIDs)).
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		testCase.setup(ctx)
+		watched, err := repo.ListWatchedMovieIDs(ctx, "user-id", testCase.req.([]string))
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error())
+		} else {
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedResp, watched)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"remi/internal/entities"
+	"remi/internal/repositories"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+const defaultAdminListLimit = 20
+
+var _ up.AdminService = &AdminService{}
+
+// AdminService lets moderators and admins manage users and moderate movies.
+// Required permissions are enforced by the routes, see rolePermissions.
+type AdminService struct {
+	userRepo         *repositories.UserRepository
+	movieRepo        *repositories.MovieRepository
+	reportRepo       *repositories.ReportRepository
+	refreshTokenRepo *repositories.RefreshTokenRepository
+}
+
+func NewAdminService(db *sql.DB) *AdminService {
+	return &AdminService{
+		userRepo:         repositories.NewUserRepository(db),
+		movieRepo:        repositories.NewMovieRepository(db),
+		reportRepo:       repositories.NewReportRepository(db),
+		refreshTokenRepo: repositories.NewRefreshTokenRepository(db),
+	}
+}
+
+func (s *AdminService) ListUsers(ctx context.Context, req *up.ListUsersRequest) (*up.ListUsersResponse, error) {
+	paging := adminPaging(req.Offset, req.Limit)
+	users, err := s.userRepo.ListAll(ctx, &repositories.ListAllUsersArgs{
+		Offset: paging.Offset,
+		Limit:  paging.Limit,
+	})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.ListAll: %w", err))
+	}
+
+	resp := &up.ListUsersResponse{
+		Users:  make([]*up.AdminUser, 0, len(users)),
+		Paging: paging,
+	}
+	for _, user := range users {
+		resp.Users = append(resp.Users, toAdminUser(user))
+	}
+
+	return resp, nil
+}
+
+func (s *AdminService) ChangeUserRole(ctx context.Context, req *up.ChangeUserRoleRequest) (*up.ChangeUserRoleResponse, error) {
+	if !isValidRole(req.Role) {
+		return nil, xerror.ErrorMf(xerror.InvalidArgument, nil, "role (%s) is invalid", req.Role)
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	if req.UserID == userID {
+		return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "you can't change your own role")
+	}
+
+	user, err := s.findUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.userRepo.UpdateRole(ctx, user.ID, req.Role, now); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.UpdateRole: %w", err))
+	}
+	user.Role = req.Role
+	user.UpdatedAt = &now
+
+	return &up.ChangeUserRoleResponse{
+		AdminUser: *toAdminUser(user),
+	}, nil
+}
+
+// DisableUser bans the user: the account can't log in anymore and its tokens are revoked,
+// so they stay revoked once the user is enabled again
+func (s *AdminService) DisableUser(ctx context.Context, req *up.DisableUserRequest) (*up.DisableUserResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	if req.UserID == userID {
+		return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "you can't disable your own account")
+	}
+
+	user, err := s.findUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanBan(ctx, user); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.userRepo.SetDisabled(ctx, user.ID, &now, now); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.SetDisabled: %w", err))
+	}
+	if err := s.userRepo.InvalidateTokens(ctx, user.ID, now); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.InvalidateTokens: %w", err))
+	}
+	if err := s.refreshTokenRepo.RevokeByUserID(ctx, user.ID, now); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.refreshTokenRepo.RevokeByUserID: %w", err))
+	}
+
+	return &up.DisableUserResponse{}, nil
+}
+
+func (s *AdminService) EnableUser(ctx context.Context, req *up.EnableUserRequest) (*up.EnableUserResponse, error) {
+	user, err := s.findUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanBan(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetDisabled(ctx, user.ID, nil, time.Now()); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.SetDisabled: %w", err))
+	}
+
+	return &up.EnableUserResponse{}, nil
+}
+
+// HideMovie hides the movie from every listing and resolves its pending reports
+func (s *AdminService) HideMovie(ctx context.Context, req *up.HideMovieRequest) (*up.HideMovieResponse, error) {
+	if _, err := s.movieRepo.FindByID(ctx, req.MovieID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", req.MovieID)
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	now := time.Now()
+	if err := s.movieRepo.SetHidden(ctx, req.MovieID, &userID, now); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.SetHidden: %w", err))
+	}
+	if err := s.reportRepo.ResolveByMovieID(ctx, req.MovieID, now); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.reportRepo.ResolveByMovieID: %w", err))
+	}
+
+	return &up.HideMovieResponse{}, nil
+}
+
+func (s *AdminService) UnhideMovie(ctx context.Context, req *up.UnhideMovieRequest) (*up.UnhideMovieResponse, error) {
+	if err := s.movieRepo.SetHidden(ctx, req.MovieID, nil, time.Now()); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.SetHidden: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", req.MovieID)
+	}
+
+	return &up.UnhideMovieResponse{}, nil
+}
+
+func (s *AdminService) ListReports(ctx context.Context, req *up.ListReportsRequest) (*up.ListReportsResponse, error) {
+	paging := adminPaging(req.Offset, req.Limit)
+	reports, err := s.reportRepo.ListUnresolved(ctx, &repositories.ListReportsArgs{
+		Offset: paging.Offset,
+		Limit:  paging.Limit,
+	})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.reportRepo.ListUnresolved: %w", err))
+	}
+
+	resp := &up.ListReportsResponse{
+		Reports: make([]*up.Report, 0, len(reports)),
+		Paging:  paging,
+	}
+	for _, report := range reports {
+		resp.Reports = append(resp.Reports, &up.Report{
+			ID:         report.ID,
+			MovieID:    report.MovieID,
+			ReportedBy: report.UserID,
+			Reason:     report.Reason,
+			CreatedAt:  *report.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *AdminService) findUser(ctx context.Context, id string) (*entities.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "user (%s) not found", id)
+	}
+
+	return user, nil
+}
+
+// checkCanBan only lets admins ban other moderators and admins
+func (s *AdminService) checkCanBan(ctx context.Context, user *entities.User) error {
+	if user.Role == entities.RoleUser {
+		return nil
+	}
+
+	role, _ := roleFromCtx(ctx)
+	if !hasPermission(role, PermissionManageRoles) {
+		return xerror.ErrorM(xerror.PermissionDenied, nil, "only admins can ban moderators and admins")
+	}
+
+	return nil
+}
+
+func adminPaging(offset, limit *int) up.OffsetPaging {
+	paging := up.OffsetPaging{
+		Limit: defaultAdminListLimit,
+	}
+	if offset != nil {
+		paging.Offset = *offset
+	}
+	if limit != nil {
+		paging.Limit = *limit
+	}
+
+	return paging
+}
+
+func toAdminUser(user *entities.User) *up.AdminUser {
+	return &up.AdminUser{
+		ID:         user.ID,
+		Username:   user.Username,
+		Name:       user.Name,
+		Role:       user.Role,
+		DisabledAt: user.DisabledAt,
+		CreatedAt:  *user.CreatedAt,
+	}
+}
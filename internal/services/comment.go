@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/internal/repositories"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/golibs/idutil"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+const (
+	defaultCommentsLimit = 20
+	// repliesLimit caps the replies listed under each comment
+	repliesLimit = 20
+)
+
+var _ up.CommentService = &CommentService{}
+
+type CommentService struct {
+	commentRepo *repositories.CommentRepository
+	movieRepo   *repositories.MovieRepository
+	userRepo    *repositories.UserRepository
+}
+
+func NewCommentService(db *sql.DB) *CommentService {
+	return &CommentService{
+		commentRepo: repositories.NewCommentRepository(db),
+		movieRepo:   repositories.NewMovieRepository(db),
+		userRepo:    repositories.NewUserRepository(db),
+	}
+}
+
+func (s *CommentService) CreateComment(ctx context.Context, req *up.CreateCommentRequest) (*up.CreateCommentResponse, error) {
+	if err := s.findMovie(ctx, req.MovieID); err != nil {
+		return nil, err
+	}
+
+	var parentID *string
+	if req.ParentID != "" {
+		parent, err := s.findComment(ctx, req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.MovieID != req.MovieID {
+			return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "parent comment belongs to another movie")
+		}
+
+		// threads are one level deep, replying to a reply joins the thread of its parent
+		parentID = &parent.ID
+		if parent.ParentID != nil {
+			parentID = parent.ParentID
+		}
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	now := time.Now()
+	comment := &entities.Comment{
+		ID:        idutil.NewID(),
+		MovieID:   req.MovieID,
+		UserID:    userID,
+		ParentID:  parentID,
+		Content:   strings.TrimSpace(req.Content),
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.commentRepo.Create: %w", err))
+	}
+
+	comments, err := s.toComments(ctx, entities.Comments{comment})
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.CreateCommentResponse{
+		Comment: *comments[0],
+	}, nil
+}
+
+func (s *CommentService) EditComment(ctx context.Context, req *up.EditCommentRequest) (*up.EditCommentResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	comment, err := s.findOwnedComment(ctx, req.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment.Content = strings.TrimSpace(req.Content)
+	comment.UpdatedAt = &now
+	if err := s.commentRepo.UpdateContent(ctx, comment); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.commentRepo.UpdateContent: %w", err))
+	}
+
+	comments, err := s.toComments(ctx, entities.Comments{comment})
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.EditCommentResponse{
+		Comment: *comments[0],
+	}, nil
+}
+
+func (s *CommentService) DeleteComment(ctx context.Context, req *up.DeleteCommentRequest) (*up.DeleteCommentResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	if _, err := s.findOwnedComment(ctx, req.ID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.SoftDelete(ctx, req.ID, userID, time.Now()); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.commentRepo.SoftDelete: %w", err))
+	}
+
+	return &up.DeleteCommentResponse{}, nil
+}
+
+// ListComments lists top level comments of a movie, newest first, each with its oldest replies
+func (s *CommentService) ListComments(ctx context.Context, req *up.ListCommentsRequest) (*up.ListCommentsResponse, error) {
+	if err := s.findMovie(ctx, req.MovieID); err != nil {
+		return nil, err
+	}
+
+	limit := defaultCommentsLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+
+	args := &repositories.ListCommentsArgs{
+		MovieID: req.MovieID,
+		// fetch one more comment to know if there is a next page
+		Limit: limit + 1,
+	}
+	if req.Cursor != "" {
+		after, err := cursor.Decode(req.Cursor)
+		if err != nil {
+			return nil, xerror.ErrorM(xerror.InvalidArgument, err, "invalid cursor")
+		}
+		args.After = after
+	}
+
+	comments, err := s.commentRepo.List(ctx, args)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.commentRepo.List: %w", err))
+	}
+
+	resp := &up.ListCommentsResponse{}
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[limit-1]
+		resp.NextCursor = cursor.Encode(&cursor.Cursor{CreatedAt: *last.CreatedAt, ID: last.ID})
+	}
+	if len(comments) == 0 {
+		return resp, nil
+	}
+
+	parentIDs := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		parentIDs = append(parentIDs, comment.ID)
+	}
+	// fetch one more reply per comment to know if some are left out
+	replies, err := s.commentRepo.ListReplies(ctx, parentIDs, repliesLimit+1)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.commentRepo.ListReplies: %w", err))
+	}
+
+	thread, err := s.toComments(ctx, append(comments, replies...))
+	if err != nil {
+		return nil, err
+	}
+
+	commentMap := make(map[string]*up.Comment)
+	for _, comment := range thread[:len(comments)] {
+		commentMap[comment.ID] = comment
+		resp.Comments = append(resp.Comments, comment)
+	}
+	for _, reply := range thread[len(comments):] {
+		parent, ok := commentMap[reply.ParentID]
+		if !ok {
+			continue
+		}
+		if len(parent.Replies) == repliesLimit {
+			if parent.RepliesCursor == "" {
+				last := parent.Replies[repliesLimit-1]
+				parent.RepliesCursor = cursor.Encode(&cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+			}
+			continue
+		}
+		parent.Replies = append(parent.Replies, reply)
+	}
+
+	return resp, nil
+}
+
+// ListReplies lists the replies of a comment after those listed with it, oldest first
+func (s *CommentService) ListReplies(ctx context.Context, req *up.ListRepliesRequest) (*up.ListRepliesResponse, error) {
+	if _, err := s.findComment(ctx, req.ID); err != nil {
+		return nil, err
+	}
+
+	limit := defaultCommentsLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+
+	args := &repositories.ListRepliesArgs{
+		ParentID: req.ID,
+		// fetch one more reply to know if there is a next page
+		Limit: limit + 1,
+	}
+	if req.Cursor != "" {
+		after, err := cursor.Decode(req.Cursor)
+		if err != nil {
+			return nil, xerror.ErrorM(xerror.InvalidArgument, err, "invalid cursor")
+		}
+		args.After = after
+	}
+
+	replies, err := s.commentRepo.ListRepliesOf(ctx, args)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.commentRepo.ListRepliesOf: %w", err))
+	}
+
+	resp := &up.ListRepliesResponse{}
+	if len(replies) > limit {
+		replies = replies[:limit]
+		last := replies[limit-1]
+		resp.NextCursor = cursor.Encode(&cursor.Cursor{CreatedAt: *last.CreatedAt, ID: last.ID})
+	}
+	if len(replies) == 0 {
+		return resp, nil
+	}
+
+	resp.Replies, err = s.toComments(ctx, replies)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *CommentService) findMovie(ctx context.Context, id string) error {
+	if _, err := s.movieRepo.FindByID(ctx, id); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.FindByID: %w", err))
+		}
+		return xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", id)
+	}
+
+	return nil
+}
+
+func (s *CommentService) findComment(ctx context.Context, id string) (*entities.Comment, error) {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.commentRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "comment (%s) not found", id)
+	}
+
+	return comment, nil
+}
+
+// findOwnedComment finds a comment which userID is allowed to mutate
+func (s *CommentService) findOwnedComment(ctx context.Context, id, userID string) (*entities.Comment, error) {
+	comment, err := s.findComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID != userID {
+		return nil, xerror.ErrorM(xerror.PermissionDenied, nil, "only the author can change the comment")
+	}
+
+	return comment, nil
+}
+
+// toComments converts comments to responses, looking up their authors in one query
+func (s *CommentService) toComments(ctx context.Context, comments entities.Comments) ([]*up.Comment, error) {
+	userIDs := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		userIDs = append(userIDs, comment.UserID)
+	}
+
+	users, err := s.userRepo.List(ctx, &repositories.ListUsersArgs{IDs: userIDs})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.List: %w", err))
+	}
+	userMap := make(map[string]*entities.User)
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+
+	result := make([]*up.Comment, 0, len(comments))
+	for _, comment := range comments {
+		c := &up.Comment{
+			ID:        comment.ID,
+			MovieID:   comment.MovieID,
+			Content:   comment.Content,
+			UserID:    comment.UserID,
+			CreatedAt: *comment.CreatedAt,
+			UpdatedAt: *comment.UpdatedAt,
+		}
+		if comment.ParentID != nil {
+			c.ParentID = *comment.ParentID
+		}
+		if user, ok := userMap[comment.UserID]; ok {
+			c.Author = user.Name
+		}
+		result = append(result, c)
+	}
+
+	return result, nil
+}
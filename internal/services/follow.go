@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"remi/internal/entities"
+	"remi/internal/repositories"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+const defaultFollowsLimit = 20
+
+var _ up.FollowService = &FollowService{}
+
+type FollowService struct {
+	followRepo *repositories.FollowRepository
+	userRepo   *repositories.UserRepository
+	url        string
+}
+
+func NewFollowService(db *sql.DB, url string) *FollowService {
+	return &FollowService{
+		followRepo: repositories.NewFollowRepository(db),
+		userRepo:   repositories.NewUserRepository(db),
+		url:        url,
+	}
+}
+
+// FollowUser follows the user, following them again is a no-op
+func (s *FollowService) FollowUser(ctx context.Context, req *up.FollowUserRequest) (*up.FollowUserResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	if req.UserID == userID {
+		return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "you can't follow yourself")
+	}
+
+	if _, err := s.findUser(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err := s.followRepo.Create(ctx, &entities.Follow{
+		FollowerID: userID,
+		FolloweeID: req.UserID,
+		CreatedAt:  &now,
+	})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.followRepo.Create: %w", err))
+	}
+
+	return &up.FollowUserResponse{}, nil
+}
+
+// UnfollowUser is a no-op when the caller doesn't follow the user, it doesn't look the user up
+// so that banned users can still be unfollowed
+func (s *FollowService) UnfollowUser(ctx context.Context, req *up.UnfollowUserRequest) (*up.UnfollowUserResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	if err := s.followRepo.Delete(ctx, userID, req.UserID); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.followRepo.Delete: %w", err))
+	}
+
+	return &up.UnfollowUserResponse{}, nil
+}
+
+func (s *FollowService) ListFollowers(ctx context.Context, req *up.ListFollowersRequest) (*up.ListFollowersResponse, error) {
+	users, nextCursor, err := s.listFollows(ctx, req.UserID, req.Cursor, req.Limit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.ListFollowersResponse{
+		Users:      users,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (s *FollowService) ListFollowing(ctx context.Context, req *up.ListFollowingRequest) (*up.ListFollowingResponse, error) {
+	users, nextCursor, err := s.listFollows(ctx, req.UserID, req.Cursor, req.Limit, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.ListFollowingResponse{
+		Users:      users,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// listFollows lists the users following userID, or followed by userID when following is set
+func (s *FollowService) listFollows(ctx context.Context, userID, pageCursor string, limit *int, following bool) ([]*up.FollowUser, string, error) {
+	if _, err := s.findUser(ctx, userID); err != nil {
+		return nil, "", err
+	}
+
+	args := &repositories.ListFollowsArgs{
+		UserID: userID,
+		// fetch one more follow to know if there is a next page
+		Limit: defaultFollowsLimit + 1,
+	}
+	if limit != nil {
+		args.Limit = *limit + 1
+	}
+	if pageCursor != "" {
+		after, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, "", xerror.ErrorM(xerror.InvalidArgument, err, "invalid cursor")
+		}
+		args.After = after
+	}
+
+	var follows entities.Follows
+	var err error
+	if following {
+		follows, err = s.followRepo.ListFollowing(ctx, args)
+		if err != nil {
+			return nil, "", xerror.Error(xerror.Internal, fmt.Errorf("s.followRepo.ListFollowing: %w", err))
+		}
+	} else {
+		follows, err = s.followRepo.ListFollowers(ctx, args)
+		if err != nil {
+			return nil, "", xerror.Error(xerror.Internal, fmt.Errorf("s.followRepo.ListFollowers: %w", err))
+		}
+	}
+
+	// the listed user of a follow is the other side of it
+	listedID := func(f *entities.Follow) string {
+		if following {
+			return f.FolloweeID
+		}
+		return f.FollowerID
+	}
+
+	var nextCursor string
+	if len(follows) == args.Limit {
+		follows = follows[:args.Limit-1]
+		last := follows[len(follows)-1]
+		nextCursor = cursor.Encode(&cursor.Cursor{CreatedAt: *last.CreatedAt, ID: listedID(last)})
+	}
+	if len(follows) == 0 {
+		return nil, nextCursor, nil
+	}
+
+	userIDs := make([]string, 0, len(follows))
+	for _, follow := range follows {
+		userIDs = append(userIDs, listedID(follow))
+	}
+	users, err := s.userRepo.List(ctx, &repositories.ListUsersArgs{IDs: userIDs})
+	if err != nil {
+		return nil, "", xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.List: %w", err))
+	}
+	userMap := make(map[string]*entities.User)
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+
+	result := make([]*up.FollowUser, 0, len(follows))
+	for _, follow := range follows {
+		user, ok := userMap[listedID(follow)]
+		if !ok || user.DisabledAt != nil {
+			continue
+		}
+		result = append(result, &up.FollowUser{
+			ID:         user.ID,
+			Username:   user.Username,
+			Name:       user.Name,
+			AvatarURL:  avatarURL(s.url, user.AvatarKey),
+			FollowedAt: *follow.CreatedAt,
+		})
+	}
+
+	return result, nextCursor, nil
+}
+
+// findUser finds a user who can be followed, banned users are reported as not found
+func (s *FollowService) findUser(ctx context.Context, id string) (*entities.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "user (%s) not found", id)
+	}
+
+	if user.DisabledAt != nil {
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "user (%s) not found", id)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"remi/internal/entities"
+	"remi/internal/repositories"
+	"remi/pkg/config"
+	"remi/pkg/golibs/idutil"
+	"remi/pkg/xerror"
+)
+
+// loginFailuresTTL is how long failed logins are remembered, the counters start over afterwards
+const loginFailuresTTL = 24 * time.Hour
+
+// loginLimiter counts failed logins per username and per IP and locks them out for an
+// exponentially growing duration once they reach their maximum attempts
+type loginLimiter struct {
+	attemptRepo *repositories.LoginAttemptRepository
+	lockoutRepo *repositories.LoginLockoutRepository
+	cfg         config.LoginLockout
+}
+
+func newLoginLimiter(db *sql.DB, cfg config.LoginLockout) *loginLimiter {
+	return &loginLimiter{
+		attemptRepo: repositories.NewLoginAttemptRepository(db),
+		lockoutRepo: repositories.NewLoginLockoutRepository(db),
+		cfg:         cfg,
+	}
+}
+
+// subjects lists the counters of a login, a scope is disabled when its maximum attempts isn't positive
+func (l *loginLimiter) subjects(username, ip string) map[string]string {
+	subjects := make(map[string]string)
+	if l.cfg.MaxAttempts > 0 {
+		subjects[entities.LoginScopeUsername] = username
+	}
+	if l.cfg.MaxAttemptsPerIP > 0 && ip != "" {
+		subjects[entities.LoginScopeIP] = ip
+	}
+	return subjects
+}
+
+func (l *loginLimiter) maxAttempts(scope string) int {
+	if scope == entities.LoginScopeIP {
+		return l.cfg.MaxAttemptsPerIP
+	}
+	return l.cfg.MaxAttempts
+}
+
+// check returns a RateLimited error when the username or the IP is locked out
+func (l *loginLimiter) check(ctx context.Context, username, ip string) error {
+	now := time.Now()
+	for scope, subject := range l.subjects(username, ip) {
+		attempt, err := l.attemptRepo.Find(ctx, scope, subject)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return xerror.Error(xerror.Internal, fmt.Errorf("l.attemptRepo.Find: %w", err))
+		}
+
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			retryIn := attempt.LockedUntil.Sub(now).Round(time.Second)
+			return xerror.ErrorMf(xerror.RateLimited, nil, "too many failed login attempts, try again in %v", retryIn)
+		}
+	}
+
+	return nil
+}
+
+// fail counts a failed login and locks out the username or the IP when it's one too many
+func (l *loginLimiter) fail(ctx context.Context, username, ip string) error {
+	now := time.Now()
+	for scope, subject := range l.subjects(username, ip) {
+		attempt, err := l.attemptRepo.RecordFailure(ctx, scope, subject, now, now.Add(-loginFailuresTTL))
+		if err != nil {
+			return xerror.Error(xerror.Internal, fmt.Errorf("l.attemptRepo.RecordFailure: %w", err))
+		}
+
+		excess := attempt.Failures - l.maxAttempts(scope)
+		if excess < 0 {
+			continue
+		}
+
+		lockedUntil := now.Add(l.lockoutDuration(excess))
+		if err := l.attemptRepo.Lock(ctx, scope, subject, lockedUntil); err != nil {
+			return xerror.Error(xerror.Internal, fmt.Errorf("l.attemptRepo.Lock: %w", err))
+		}
+
+		err = l.lockoutRepo.Create(ctx, &entities.LoginLockout{
+			ID:          idutil.NewID(),
+			Scope:       scope,
+			Subject:     subject,
+			IP:          ip,
+			Failures:    attempt.Failures,
+			LockedUntil: &lockedUntil,
+			CreatedAt:   &now,
+		})
+		if err != nil {
+			return xerror.Error(xerror.Internal, fmt.Errorf("l.lockoutRepo.Create: %w", err))
+		}
+		log.Printf("login lockout: %s %q locked until %s after %d failures from %s", scope, subject, lockedUntil.Format(time.RFC3339), attempt.Failures, ip)
+	}
+
+	return nil
+}
+
+// succeed forgets the failed logins of the username, those of the IP expire on their own so
+// that an attacker can't reset them by logging into their own account
+func (l *loginLimiter) succeed(ctx context.Context, username string) error {
+	if l.cfg.MaxAttempts <= 0 {
+		return nil
+	}
+	if err := l.attemptRepo.Delete(ctx, entities.LoginScopeUsername, username); err != nil {
+		return xerror.Error(xerror.Internal, fmt.Errorf("l.attemptRepo.Delete: %w", err))
+	}
+
+	return nil
+}
+
+// lockoutDuration doubles the lockout for every failure after the maximum attempts,
+// a MaxDuration which isn't positive leaves it uncapped
+func (l *loginLimiter) lockoutDuration(excess int) time.Duration {
+	d := l.cfg.Duration
+	for i := 0; i < excess && (l.cfg.MaxDuration <= 0 || d < l.cfg.MaxDuration); i++ {
+		// stops doubling before the duration overflows
+		if d > math.MaxInt64/2 {
+			break
+		}
+		d *= 2
+	}
+	if l.cfg.MaxDuration > 0 && d > l.cfg.MaxDuration {
+		d = l.cfg.MaxDuration
+	}
+
+	return d
+}
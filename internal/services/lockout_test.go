@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"remi/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLimiter_lockoutDuration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      config.LoginLockout
+		excess   int
+		expected time.Duration
+	}{
+		{
+			name:     "at the maximum attempts",
+			cfg:      config.LoginLockout{Duration: time.Minute, MaxDuration: time.Hour},
+			excess:   0,
+			expected: time.Minute,
+		},
+		{
+			name:     "doubles with every failure",
+			cfg:      config.LoginLockout{Duration: time.Minute, MaxDuration: time.Hour},
+			excess:   3,
+			expected: 8 * time.Minute,
+		},
+		{
+			name:     "capped by the max duration",
+			cfg:      config.LoginLockout{Duration: time.Minute, MaxDuration: time.Hour},
+			excess:   10,
+			expected: time.Hour,
+		},
+		{
+			name:     "max duration lower than the duration",
+			cfg:      config.LoginLockout{Duration: time.Hour, MaxDuration: time.Minute},
+			excess:   1,
+			expected: time.Minute,
+		},
+		{
+			name:     "no max duration",
+			cfg:      config.LoginLockout{Duration: time.Minute, MaxDuration: 0},
+			excess:   10,
+			expected: 1024 * time.Minute,
+		},
+		{
+			name:     "no max duration doesn't overflow",
+			cfg:      config.LoginLockout{Duration: time.Minute, MaxDuration: 0},
+			excess:   1000,
+			expected: time.Minute << 27,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			l := &loginLimiter{cfg: testCase.cfg}
+			actual := l.lockoutDuration(testCase.excess)
+			assert.Equal(t, testCase.expected, actual)
+			assert.Greater(t, int64(actual), int64(0))
+		})
+	}
+}
@@ -3,79 +3,180 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"html/template"
+	"log"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
 	"remi/internal/entities"
 	"remi/internal/repositories"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/golibs/database"
 	"remi/pkg/golibs/idutil"
+	"remi/pkg/videoprovider"
 	"remi/pkg/xerror"
 	"remi/up"
 )
 
+const defaultMoviesLimit = 10
+
 var _ up.MovieService = &MovieService{}
 
 type MovieService struct {
-	movieRepo *repositories.MovieRepository
-	userRepo  *repositories.UserRepository
-	url       string
+	db           *sql.DB
+	movieRepo    *repositories.MovieRepository
+	userRepo     *repositories.UserRepository
+	reactionRepo *repositories.ReactionRepository
+	reportRepo   *repositories.ReportRepository
+	tagRepo      *repositories.TagRepository
+	historyRepo  *repositories.WatchHistoryRepository
+	comments     *CommentService
+	hub          *NotificationHub
+	resolver     *videoprovider.Resolver
+	// metadata is nil when metadata enrichment is disabled
+	metadata videoprovider.MetadataFetcher
+	url      string
 }
 
-func NewMovieService(db *sql.DB, url string) *MovieService {
+func NewMovieService(db *sql.DB, url string, hub *NotificationHub, metadata videoprovider.MetadataFetcher, comments *CommentService) *MovieService {
 	return &MovieService{
-		userRepo:  repositories.NewUserRepository(db),
-		movieRepo: repositories.NewMovieRepository(db),
-		url:       url,
+		db:           db,
+		userRepo:     repositories.NewUserRepository(db),
+		movieRepo:    repositories.NewMovieRepository(db),
+		reactionRepo: repositories.NewReactionRepository(db),
+		reportRepo:   repositories.NewReportRepository(db),
+		tagRepo:      repositories.NewTagRepository(db),
+		historyRepo:  repositories.NewWatchHistoryRepository(db),
+		comments:     comments,
+		hub:          hub,
+		resolver:     videoprovider.DefaultResolver(),
+		metadata:     metadata,
+		url:          url,
 	}
 }
 
 func (s *MovieService) Create(ctx context.Context, req *up.CreateMovieRequest) (*up.CreateMovieResponse, error) {
-	if err := req.Validate(); err != nil {
-		return nil, xerror.Error(xerror.InvalidArgument, err)
-	}
-
-	if !strings.Contains(req.Link, "youtube.com") {
-		return nil, xerror.Error(xerror.InvalidArgument, fmt.Errorf("we only support link youtube"))
-	}
-
-	_url, err := url.Parse(req.Link)
+	tags, err := normalizeTags("tags", req.Tags)
 	if err != nil {
-		return nil, xerror.Error(xerror.Internal, fmt.Errorf("url.Parse: %w", err))
+		return nil, err
 	}
-	params, err := url.ParseQuery(_url.RawQuery)
+
+	video, err := s.resolver.Resolve(req.Link)
 	if err != nil {
-		return nil, xerror.Error(xerror.Internal, fmt.Errorf("url.ParseQuery: %w", err))
+		return nil, xerror.ErrorM(xerror.InvalidArgument, err, "unsupported video link")
 	}
 
-	youtubeVideoID := params["v"][0]
-
 	userID, _ := userIDFromCtx(ctx)
 
 	now := time.Now()
 	movieEnt := &entities.Movie{
 		ID:          idutil.NewID(),
-		Name:        req.Name,
-		Description: req.Description,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
 		Link:        req.Link,
-		Thumbnail:   fmt.Sprintf("https://img.youtube.com/vi/%s/0.jpg", youtubeVideoID),
+		Thumbnail:   video.Thumbnail,
+		Provider:    video.Provider,
 		SharedBy:    userID,
 		SharedAt:    &now,
 		CreatedAt:   &now,
 		UpdatedAt:   &now,
 	}
-	if err := s.movieRepo.Create(ctx, movieEnt); err != nil {
+	s.enrich(ctx, movieEnt, video)
+	if movieEnt.Name == "" {
+		return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "name can't be null")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByID: %w", err))
+	}
+
+	// the movie is never listed without its tags
+	err = database.ExecInTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.movieRepo.WithTx(tx).Create(ctx, movieEnt); err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+
+		tagEnts := make(entities.Tags, 0, len(tags))
+		for _, tag := range tags {
+			tagEnts = append(tagEnts, &entities.Tag{ID: idutil.NewID(), Name: tag})
+		}
+		if err := s.tagRepo.WithTx(tx).AddToMovie(ctx, movieEnt.ID, tagEnts, now); err != nil {
+			return fmt.Errorf("s.tagRepo.AddToMovie: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, xerror.Error(xerror.Internal, err)
 	}
 
+	s.hub.Publish(&Notification{
+		Type: NotificationMovieShared,
+		Data: &MovieSharedNotification{
+			ID:       movieEnt.ID,
+			Name:     movieEnt.Name,
+			SharedBy: user.Name,
+		},
+	}, userID)
+
 	return &up.CreateMovieResponse{
 		ID: movieEnt.ID,
 	}, nil
 }
 
+// enrich fills the blank fields of the movie with the metadata of the video.
+// Failing to fetch the metadata isn't fatal, the movie keeps what the user typed.
+func (s *MovieService) enrich(ctx context.Context, movie *entities.Movie, video *videoprovider.Video) {
+	if s.metadata == nil {
+		return
+	}
+
+	metadata, err := s.metadata.FetchMetadata(ctx, video.URL, video)
+	if err != nil {
+		log.Printf("s.metadata.FetchMetadata: %v", err)
+		return
+	}
+
+	if movie.Name == "" {
+		movie.Name = metadata.Title
+	}
+	if movie.Description == "" {
+		movie.Description = metadata.Description
+	}
+	if metadata.Thumbnail != "" {
+		movie.Thumbnail = metadata.Thumbnail
+	}
+	movie.Author = metadata.Author
+	movie.Duration = metadata.Duration
+}
+
+// GetMovie finds a movie shared by anyone
+func (s *MovieService) GetMovie(ctx context.Context, req *up.GetMovieRequest) (*up.GetMovieResponse, error) {
+	movie, err := s.movieRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", req.ID)
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	movies, err := s.toMovies(ctx, entities.Movies{movie}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.GetMovieResponse{
+		Movie: *movies[0],
+	}, nil
+}
+
 func (s *MovieService) GetMovieByUser(ctx context.Context, req *up.GetMovieByUserRequest) (*up.GetMovieByUserResponse, error) {
 	userID, _ := userIDFromCtx(ctx)
 	movie, err := s.movieRepo.FindByIDAndUserID(ctx, req.ID, userID)
@@ -83,7 +184,7 @@ func (s *MovieService) GetMovieByUser(ctx context.Context, req *up.GetMovieByUse
 		if err != sql.ErrNoRows {
 			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.Get: %w", err))
 		}
-		return nil, xerror.Error(xerror.InvalidArgument, fmt.Errorf("movie (%s) not found", req.ID))
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", req.ID)
 	}
 
 	user, err := s.userRepo.FindByID(ctx, userID)
@@ -93,67 +194,239 @@ func (s *MovieService) GetMovieByUser(ctx context.Context, req *up.GetMovieByUse
 
 	return &up.GetMovieByUserResponse{
 		Movie: up.Movie{
-			ID:          movie.ID,
-			Name:        movie.Name,
-			Link:        movie.Link,
-			Thumbnail:   movie.Thumbnail,
-			Description: movie.Description,
-			SharedBy:    user.Name,
-			SharedAt:    *movie.SharedAt,
+			ID:             movie.ID,
+			Name:           movie.Name,
+			Link:           movie.Link,
+			Thumbnail:      movie.Thumbnail,
+			Provider:       movie.Provider,
+			Author:         movie.Author,
+			Duration:       movie.Duration,
+			Description:    movie.Description,
+			SharedBy:       user.Name,
+			SharedByAvatar: avatarURL(s.url, user.AvatarKey),
+			SharedAt:       *movie.SharedAt,
 		},
 	}, nil
 }
 
 func (s *MovieService) ListMoviesByUser(ctx context.Context, req *up.ListMoviesByUserRequest) (resp *up.ListMoviesByUserResponse, _ error) {
 	userID, _ := userIDFromCtx(ctx)
-	movies, err := s.movieRepo.List(
-		ctx,
-		&repositories.ListMoviesArgs{
-			UserID: &userID,
-		},
-	)
+	page, err := s.listMovies(ctx, &repositories.ListMoviesArgs{UserID: &userID}, req.Cursor, req.Offset, req.Limit)
 	if err != nil {
-		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.List: %w", err))
+		return nil, err
 	}
 
-	user, err := s.userRepo.FindByID(ctx, userID)
+	resp = &up.ListMoviesByUserResponse{
+		OffsetPaging: page.paging,
+		NextCursor:   page.nextCursor,
+	}
+	resp.Movies, err = s.toMovies(ctx, page.movies, userID)
 	if err != nil {
-		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByID: %w", err))
+		return nil, err
 	}
 
-	resp = &up.ListMoviesByUserResponse{
-		OffsetPaging: &up.OffsetPaging{
-			Limit:  *req.Limit,
-			Offset: *req.Offset,
+	return resp, nil
+}
+
+// ListMoviesByUsername lists the movies shared by the user with their public profile, banned users aren't found
+func (s *MovieService) ListMoviesByUsername(ctx context.Context, req *up.ListMoviesByUsernameRequest) (resp *up.ListMoviesByUsernameResponse, _ error) {
+	user, err := s.findUserByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.movieRepo.CountByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.CountByUserID: %w", err))
+	}
+
+	page, err := s.listMovies(ctx, &repositories.ListMoviesArgs{UserID: &user.ID}, req.Cursor, req.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &up.ListMoviesByUsernameResponse{
+		User: &up.PublicUser{
+			ID:        user.ID,
+			Username:  user.Username,
+			Name:      user.Name,
+			AvatarURL: avatarURL(s.url, user.AvatarKey),
+			Shares:    shares,
+			CreatedAt: *user.CreatedAt,
 		},
+		OffsetPaging: page.paging,
+		NextCursor:   page.nextCursor,
 	}
-	for _, movie := range movies {
-		resp.Movies = append(resp.Movies, &up.Movie{
-			ID:          movie.ID,
-			Name:        movie.Name,
-			Description: movie.Description,
-			Link:        movie.Link,
-			Thumbnail:   movie.Thumbnail,
-			SharedBy:    user.Name,
-			SharedAt:    *movie.SharedAt,
-		})
+	viewerID, _ := userIDFromCtx(ctx)
+	resp.Movies, err = s.toMovies(ctx, page.movies, viewerID)
+	if err != nil {
+		return nil, err
 	}
 
 	return resp, nil
 }
 
+func (s *MovieService) findUserByUsername(ctx context.Context, username string) (*entities.User, error) {
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByUsername: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "user (%s) not found", username)
+	}
+
+	if user.DisabledAt != nil {
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "user (%s) not found", username)
+	}
+
+	return user, nil
+}
+
 func (s *MovieService) ListMovies(ctx context.Context, req *up.ListMoviesRequest) (resp *up.ListMoviesResponse, _ error) {
-	movies, err := s.movieRepo.List(
-		ctx,
-		&repositories.ListMoviesArgs{
-			Limit:  req.Limit,
-			Offset: req.Offset,
+	tags, err := normalizeTags("tags", req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// anonymous callers have no user id, they just don't get their own votes
+	userID, _ := userIDFromCtx(ctx)
+	page, err := s.listMovies(ctx, &repositories.ListMoviesArgs{
+		Tags:         tags,
+		MatchAllTags: req.TagMode == up.TagModeAll,
+	}, req.Cursor, req.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &up.ListMoviesResponse{
+		OffsetPaging: page.paging,
+		NextCursor:   page.nextCursor,
+	}
+	resp.Movies, err = s.toMovies(ctx, page.movies, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ListFeed lists the movies shared by the users the caller follows, newest first
+func (s *MovieService) ListFeed(ctx context.Context, req *up.ListFeedRequest) (resp *up.ListFeedResponse, _ error) {
+	userID, _ := userIDFromCtx(ctx)
+	page, err := s.listMovies(ctx, &repositories.ListMoviesArgs{FollowedBy: &userID}, req.Cursor, req.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &up.ListFeedResponse{
+		OffsetPaging: page.paging,
+		NextCursor:   page.nextCursor,
+	}
+	resp.Movies, err = s.toMovies(ctx, page.movies, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+type moviesPage struct {
+	movies     entities.Movies
+	paging     *up.OffsetPaging
+	nextCursor string
+}
+
+// listMovies lists movies matching the filters of args newest first, with keyset pagination when pageCursor
+// is set and offset pagination otherwise. The next cursor is returned in both modes.
+func (s *MovieService) listMovies(ctx context.Context, args *repositories.ListMoviesArgs, pageCursor string, offset, limit *int) (*moviesPage, error) {
+	page := &moviesPage{
+		paging: &up.OffsetPaging{
+			Limit: defaultMoviesLimit,
 		},
-	)
+	}
+	if limit != nil {
+		page.paging.Limit = *limit
+	}
+	if offset != nil {
+		page.paging.Offset = *offset
+	}
+
+	// fetch one more movie to know if there is a next page
+	fetchLimit := page.paging.Limit + 1
+	args.Offset = &page.paging.Offset
+	args.Limit = &fetchLimit
+	if pageCursor != "" {
+		after, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, xerror.ErrorM(xerror.InvalidArgument, err, "invalid cursor")
+		}
+		args.After = after
+		page.paging.Offset = 0
+	}
+
+	movies, err := s.movieRepo.List(ctx, args)
 	if err != nil {
 		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.List: %w", err))
 	}
 
+	if len(movies) > page.paging.Limit {
+		movies = movies[:page.paging.Limit]
+		last := movies[len(movies)-1]
+		page.nextCursor = cursor.Encode(&cursor.Cursor{CreatedAt: *last.CreatedAt, ID: last.ID})
+	}
+	page.movies = movies
+
+	return page, nil
+}
+
+func (s *MovieService) SearchMovies(ctx context.Context, req *up.SearchMoviesRequest) (*up.SearchMoviesResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	args := &repositories.SearchMoviesArgs{
+		Query:  strings.TrimSpace(req.Query),
+		Offset: 0,
+		Limit:  defaultMoviesLimit,
+	}
+	if req.Offset != nil {
+		args.Offset = *req.Offset
+	}
+	if req.Limit != nil {
+		args.Limit = *req.Limit
+	}
+
+	results, err := s.movieRepo.Search(ctx, args)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.Search: %w", err))
+	}
+
+	movies := make(entities.Movies, 0, len(results))
+	for _, result := range results {
+		movies = append(movies, &result.Movie)
+	}
+	converted, err := s.toMovies(ctx, movies, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &up.SearchMoviesResponse{
+		OffsetPaging: &up.OffsetPaging{
+			Offset: args.Offset,
+			Limit:  args.Limit,
+		},
+	}
+	for i, result := range results {
+		resp.Movies = append(resp.Movies, &up.MovieSearchResult{
+			Movie:                *converted[i],
+			Rank:                 result.Rank,
+			NameHighlight:        result.NameHighlight,
+			DescriptionHighlight: result.DescriptionHighlight,
+		})
+	}
+
+	return resp, nil
+}
+
+// toMovies converts movies to responses, looking up sharers, reactions and views of userID in batches
+func (s *MovieService) toMovies(ctx context.Context, movies entities.Movies, userID string) ([]*up.Movie, error) {
 	userIDs := make([]string, 0, len(movies))
 	for _, movie := range movies {
 		userIDs = append(userIDs, movie.SharedBy)
@@ -168,30 +441,168 @@ func (s *MovieService) ListMovies(ctx context.Context, req *up.ListMoviesRequest
 		userMap[user.ID] = user
 	}
 
-	resp = &up.ListMoviesResponse{
-		OffsetPaging: &up.OffsetPaging{
-			Offset: *req.Offset,
-			Limit:  *req.Limit,
-		},
+	movieIDs := make([]string, 0, len(movies))
+	for _, movie := range movies {
+		movieIDs = append(movieIDs, movie.ID)
+	}
+	tags, err := s.tagRepo.ListNamesByMovieIDs(ctx, movieIDs)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.tagRepo.ListNamesByMovieIDs: %w", err))
 	}
+
+	result := make([]*up.Movie, 0, len(movies))
 	for _, movie := range movies {
-		user := userMap[movie.SharedBy]
-		resp.Movies = append(resp.Movies, &up.Movie{
+		m := &up.Movie{
 			ID:          movie.ID,
 			Name:        movie.Name,
 			Description: movie.Description,
 			Link:        movie.Link,
 			Thumbnail:   movie.Thumbnail,
-			SharedBy:    user.Name,
+			Provider:    movie.Provider,
+			Author:      movie.Author,
+			Duration:    movie.Duration,
 			SharedAt:    *movie.SharedAt,
-		})
+			Tags:        tags[movie.ID],
+		}
+		if user, ok := userMap[movie.SharedBy]; ok {
+			m.SharedBy = user.Name
+			m.SharedByAvatar = avatarURL(s.url, user.AvatarKey)
+			m.SharedByUsername = user.Username
+		}
+		result = append(result, m)
 	}
 
-	return resp, nil
+	if err := fillReactions(ctx, s.reactionRepo, result, userID); err != nil {
+		return nil, err
+	}
+
+	if userID != "" && len(movieIDs) > 0 {
+		watched, err := s.historyRepo.ListWatchedMovieIDs(ctx, userID, movieIDs)
+		if err != nil {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.historyRepo.ListWatchedMovieIDs: %w", err))
+		}
+		for _, movie := range result {
+			movie.Watched = watched[movie.ID]
+		}
+	}
+
+	return result, nil
+}
+
+// recordWatch records that userID viewed the movie
+func (s *MovieService) recordWatch(ctx context.Context, userID, movieID string) error {
+	now := time.Now()
+	err := s.historyRepo.Record(ctx, &entities.WatchHistoryItem{
+		UserID:    userID,
+		MovieID:   movieID,
+		WatchedAt: &now,
+	})
+	if err != nil {
+		return xerror.Error(xerror.Internal, fmt.Errorf("s.historyRepo.Record: %w", err))
+	}
+
+	return nil
+}
+
+// findOwnedMovie finds a movie which userID is allowed to mutate
+func (s *MovieService) findOwnedMovie(ctx context.Context, id, userID string) (*entities.Movie, error) {
+	movie, err := s.movieRepo.FindByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", id)
+	}
+
+	if movie.SharedBy != userID {
+		return nil, xerror.ErrorM(xerror.PermissionDenied, nil, "only the owner can change the movie")
+	}
+
+	return movie, nil
+}
+
+func (s *MovieService) UpdateMovie(ctx context.Context, req *up.UpdateMovieRequest) (*up.UpdateMovieResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	movie, err := s.findOwnedMovie(ctx, req.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		movie.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		movie.Description = strings.TrimSpace(*req.Description)
+	}
+	now := time.Now()
+	movie.UpdatedAt = &now
+
+	if err := s.movieRepo.Update(ctx, movie); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.Update: %w", err))
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByID: %w", err))
+	}
+
+	return &up.UpdateMovieResponse{
+		Movie: up.Movie{
+			ID:             movie.ID,
+			Name:           movie.Name,
+			Description:    movie.Description,
+			Link:           movie.Link,
+			Thumbnail:      movie.Thumbnail,
+			Provider:       movie.Provider,
+			Author:         movie.Author,
+			Duration:       movie.Duration,
+			SharedBy:       user.Name,
+			SharedByAvatar: avatarURL(s.url, user.AvatarKey),
+			SharedAt:       *movie.SharedAt,
+		},
+	}, nil
+}
+
+func (s *MovieService) DeleteMovie(ctx context.Context, req *up.DeleteMovieRequest) (*up.DeleteMovieResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	if _, err := s.findOwnedMovie(ctx, req.ID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.movieRepo.SoftDelete(ctx, req.ID, userID, time.Now()); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.SoftDelete: %w", err))
+	}
+
+	return &up.DeleteMovieResponse{}, nil
+}
+
+// ReportMovie flags the movie for moderators
+func (s *MovieService) ReportMovie(ctx context.Context, req *up.ReportMovieRequest) (*up.ReportMovieResponse, error) {
+	if _, err := s.movieRepo.FindByID(ctx, req.MovieID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", req.MovieID)
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	now := time.Now()
+	err := s.reportRepo.Create(ctx, &entities.Report{
+		ID:        idutil.NewID(),
+		MovieID:   req.MovieID,
+		UserID:    userID,
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.reportRepo.Create: %w", err))
+	}
+
+	return &up.ReportMovieResponse{}, nil
 }
 
 func (s *MovieService) GetCreateMoviePage(w http.ResponseWriter, r *http.Request) {
-	tmpl := template.Must(template.ParseFiles("templates/movie_create.html"))
+	tmpl := template.Must(template.ParseFiles("templates/movie_create.html", "templates/session.html"))
 
 	data := Data{
 		URL: s.url,
@@ -200,50 +611,112 @@ func (s *MovieService) GetCreateMoviePage(w http.ResponseWriter, r *http.Request
 }
 
 type ViewMovieData struct {
-	Name        string
-	Link        string
-	SharedBy    string
-	Description string
+	URL              string
+	ID               string
+	Name             string
+	Link             string
+	SharedBy         string
+	SharedByAvatar   string
+	SharedByUsername string
+	Description      string
+	Author           string
+	Comments         []*up.Comment
+	NextCursor       string
 }
 
 func (s *MovieService) GetViewMoviePage(w http.ResponseWriter, r *http.Request) {
-	tmpl := template.Must(template.ParseFiles("templates/movie.html"))
+	tmpl := template.Must(template.ParseFiles("templates/movie.html", "templates/session.html"))
 
-	params := r.URL.Query()
-	ids, ok := params["id"]
-	if !ok || len(ids) == 0 {
+	// /movie/{id}, or /movie?id= for links shared before path parameters
+	id := PathParam(r, "id")
+	if id == "" {
+		id = r.URL.Query().Get("id")
+	}
+	if id == "" {
 		w.WriteHeader(http.StatusNotFound)
+		return
 	}
 
 	ctx := context.Background()
 
-	movie, err := s.movieRepo.FindByID(ctx, ids[0])
+	movie, err := s.movieRepo.FindByID(ctx, id)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
+		return
 	}
 
 	user, err := s.userRepo.FindByID(ctx, movie.SharedBy)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
+		return
 	}
 
-	_url, err := url.Parse(movie.Link)
+	video, err := s.resolver.Resolve(movie.Link)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
-	params, err = url.ParseQuery(_url.RawQuery)
+
+	comments, err := s.comments.ListComments(ctx, &up.ListCommentsRequest{MovieID: movie.ID})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 
-	youtubeVideoID := params["v"][0]
+	// browsers don't send the token when opening the page, the page records the view through
+	// the API instead, but the view is recorded here for clients which do send it
+	if userID, ok := userIDFromCtx(r.Context()); ok {
+		if err := s.recordWatch(ctx, userID, movie.ID); err != nil {
+			log.Println(err)
+		}
+	}
 
 	viewMovieData := ViewMovieData{
-		Link:        fmt.Sprintf("https://www.youtube.com/embed/%s", youtubeVideoID),
-		Name:        movie.Name,
-		Description: movie.Description,
-		SharedBy:    user.Name,
+		URL:              s.url,
+		ID:               movie.ID,
+		Link:             video.EmbedURL,
+		Name:             movie.Name,
+		Description:      movie.Description,
+		SharedBy:         user.Name,
+		SharedByAvatar:   avatarURL(s.url, user.AvatarKey),
+		SharedByUsername: user.Username,
+		Author:           movie.Author,
+		Comments:         comments.Comments,
+		NextCursor:       comments.NextCursor,
 	}
 
 	tmpl.Execute(w, viewMovieData)
 }
+
+type ViewUserData struct {
+	URL        string
+	User       *up.PublicUser
+	Movies     []*up.Movie
+	NextCursor string
+}
+
+func (s *MovieService) GetViewUserPage(w http.ResponseWriter, r *http.Request) {
+	tmpl := template.Must(template.ParseFiles("templates/user.html", "templates/session.html"))
+
+	ctx := context.Background()
+
+	resp, err := s.ListMoviesByUsername(ctx, &up.ListMoviesByUsernameRequest{Username: PathParam(r, "username")})
+	if err != nil {
+		var xErr xerror.XError
+		if errors.As(err, &xErr) && xErr.Code == xerror.NotFound {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	viewUserData := ViewUserData{
+		URL:        s.url,
+		User:       resp.User,
+		Movies:     resp.Movies,
+		NextCursor: resp.NextCursor,
+	}
+
+	tmpl.Execute(w, viewUserData)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	notificationBufferSize = 16
+	notificationKeepAlive  = 30 * time.Second
+	// notificationSessionCheck is how often streams check that their access token is still accepted
+	notificationSessionCheck = time.Minute
+
+	NotificationMovieShared = "movie_shared"
+)
+
+// Notification is an event pushed to connected users
+type Notification struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+type MovieSharedNotification struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	SharedBy string `json:"shared_by"`
+}
+
+type subscriber struct {
+	userID string
+	ch     chan *Notification
+}
+
+// NotificationHub fans out notifications to every connected subscriber
+type NotificationHub struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+func NewNotificationHub() *NotificationHub {
+	return &NotificationHub{
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+func (h *NotificationHub) subscribe(userID string) *subscriber {
+	sub := &subscriber{
+		userID: userID,
+		ch:     make(chan *Notification, notificationBufferSize),
+	}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+func (h *NotificationHub) unsubscribe(sub *subscriber) {
+	h.mu.Lock()
+	delete(h.subscribers, sub)
+	h.mu.Unlock()
+}
+
+// Publish sends the notification to every subscriber except excludeUserID.
+// Slow subscribers whose buffer is full miss the notification instead of blocking the publisher.
+func (h *NotificationHub) Publish(n *Notification, excludeUserID string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers {
+		if sub.userID == excludeUserID {
+			continue
+		}
+		select {
+		case sub.ch <- n:
+		default:
+			log.Printf("notification dropped for user %s", sub.userID)
+		}
+	}
+}
+
+// Stream serves notifications to the caller as server-sent events until the request context is done
+func (h *NotificationHub) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	userID, _ := userIDFromCtx(r.Context())
+	sub := h.subscribe(userID)
+	defer h.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(notificationKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case n := <-sub.ch:
+			data, err := json.Marshal(n.Data)
+			if err != nil {
+				log.Printf("json.Marshal: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", n.Type, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemiService_streamNotifications_ClosesAtTokenExpiry(t *testing.T) {
+	s := &RemiService{hub: NewNotificationHub()}
+
+	ctx := context.WithValue(context.Background(), userAuthKey(0), "user-id")
+	ctx = context.WithValue(ctx, userAuthKey(1), &accessToken{
+		JTI:       "jti",
+		ExpiresAt: time.Now().Add(50 * time.Millisecond),
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications", nil).WithContext(ctx)
+	resp := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		s.streamNotifications(resp, req)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the stream outlived the access token")
+	}
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"remi/pkg/openapi"
+	"remi/up"
+)
+
+//go:generate go run remi/cmd/openapi -out ../../api/openapi.json
+
+const (
+	openAPITitle   = "Remi API"
+	openAPIVersion = "2.0.0"
+
+	tokenSecurityScheme = "token"
+)
+
+// OpenAPI returns the indented OpenAPI document of the API
+func (s *RemiService) OpenAPI() ([]byte, error) {
+	data, err := json.MarshalIndent(s.router.OpenAPI(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("json.MarshalIndent: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+func (s *RemiService) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	data, err := s.OpenAPI()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (s *RemiService) GetAPIDocsPage(w http.ResponseWriter, r *http.Request) {
+	tmpl := template.Must(template.ParseFiles("templates/api_docs.html"))
+
+	data := Data{
+		URL: s.url,
+	}
+	tmpl.Execute(w, data)
+}
+
+// OpenAPI describes the JSON endpoints of the router
+func (r *Router) OpenAPI() *openapi.Document {
+	doc := openapi.NewDocument(openAPITitle, openAPIVersion)
+	doc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+		tokenSecurityScheme: {
+			Type:        "apiKey",
+			In:          "header",
+			Name:        "Authorization",
+			Description: "The token returned by login or refresh, optionally prefixed with Bearer",
+		},
+	}
+	errorSchema := doc.SchemaOf(reflect.TypeOf(up.ErrorResponse{}))
+
+	for path, methods := range r.routes {
+		for method, h := range methods {
+			if h.request == nil {
+				continue
+			}
+
+			item, ok := doc.Paths[path]
+			if !ok {
+				item = &openapi.PathItem{}
+				doc.Paths[path] = item
+			}
+			(*item)[strings.ToLower(method)] = h.operation(doc, errorSchema)
+		}
+	}
+
+	return doc
+}
+
+func (h *routeHandler) operation(doc *openapi.Document, errorSchema *openapi.Schema) *openapi.Operation {
+	op := &openapi.Operation{
+		OperationID: operationID(h.Path, h.name),
+		Tags:        []string{strings.TrimSuffix(h.service, "Service")},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("OK", doc.SchemaOf(h.response)),
+			"400": jsonResponse("Invalid argument", errorSchema),
+			"500": jsonResponse("Internal error", errorSchema),
+		},
+	}
+
+	for _, field := range openapi.Fields(h.request) {
+		if name := field.Tags.Get("path"); name != "" {
+			op.Responses["404"] = jsonResponse("Not found", errorSchema)
+			op.Parameters = append(op.Parameters, &openapi.Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   doc.SchemaOf(field.Type),
+			})
+			continue
+		}
+		if h.Method == http.MethodGet {
+			op.Parameters = append(op.Parameters, &openapi.Parameter{
+				Name:   field.Name,
+				In:     "query",
+				Schema: doc.SchemaOf(field.Type),
+			})
+		}
+	}
+	sort.SliceStable(op.Parameters, func(i, j int) bool {
+		return op.Parameters[i].In == "path" && op.Parameters[j].In != "path"
+	})
+
+	if h.Method != http.MethodGet {
+		op.RequestBody = &openapi.RequestBody{
+			Content: map[string]*openapi.MediaType{
+				"application/json": {Schema: doc.SchemaOf(h.request)},
+			},
+		}
+	}
+
+	switch h.Auth {
+	case User:
+		op.Security = []openapi.SecurityRequirement{{tokenSecurityScheme: {}}}
+		op.Responses["401"] = jsonResponse("Missing or invalid token", errorSchema)
+		op.Responses["403"] = jsonResponse("Permission denied", errorSchema)
+	case OptionalUser:
+		op.Security = []openapi.SecurityRequirement{{}, {tokenSecurityScheme: {}}}
+	}
+	if h.Permission != "" {
+		op.Description = fmt.Sprintf("Requires the %s permission.", h.Permission)
+	}
+
+	return op
+}
+
+// operationID names the operation after its handler, v1 keeps the plain name as its paths are named after handlers
+func operationID(path, handler string) string {
+	id := strings.ToLower(handler[:1]) + handler[1:]
+	segments := strings.Split(path, "/")
+	if len(segments) > 2 && segments[1] == "api" && segments[2] != "v1" {
+		id += strings.ToUpper(segments[2])
+	}
+	return id
+}
+
+func jsonResponse(description string, schema *openapi.Schema) *openapi.Response {
+	return &openapi.Response{
+		Description: description,
+		Content: map[string]*openapi.MediaType{
+			"application/json": {Schema: schema},
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"remi/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemiService_OpenAPI(t *testing.T) {
+	s := NewRemiService(nil, &config.Config{})
+
+	expected, err := os.ReadFile("../../api/openapi.json")
+	require.NoError(t, err)
+
+	actual, err := s.OpenAPI()
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(actual), "api/openapi.json is outdated, run go generate ./internal/services")
+}
@@ -0,0 +1,510 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"remi/internal/entities"
+	"remi/internal/repositories"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/golibs/idutil"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+const defaultPlaylistsLimit = 20
+
+var _ up.PlaylistService = &PlaylistService{}
+
+type PlaylistService struct {
+	playlistRepo *repositories.PlaylistRepository
+	itemRepo     *repositories.PlaylistItemRepository
+	movieRepo    *repositories.MovieRepository
+	userRepo     *repositories.UserRepository
+	movies       *MovieService
+	url          string
+}
+
+func NewPlaylistService(db *sql.DB, url string, movies *MovieService) *PlaylistService {
+	return &PlaylistService{
+		playlistRepo: repositories.NewPlaylistRepository(db),
+		itemRepo:     repositories.NewPlaylistItemRepository(db),
+		movieRepo:    repositories.NewMovieRepository(db),
+		userRepo:     repositories.NewUserRepository(db),
+		movies:       movies,
+		url:          url,
+	}
+}
+
+func (s *PlaylistService) CreatePlaylist(ctx context.Context, req *up.CreatePlaylistRequest) (*up.CreatePlaylistResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = entities.PlaylistPrivate
+	}
+
+	now := time.Now()
+	playlist := &entities.Playlist{
+		ID:          idutil.NewID(),
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Visibility:  visibility,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	}
+	if err := s.playlistRepo.Create(ctx, playlist); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.playlistRepo.Create: %w", err))
+	}
+
+	p, err := s.toPlaylistWithItems(ctx, playlist, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.CreatePlaylistResponse{
+		Playlist: *p,
+	}, nil
+}
+
+func (s *PlaylistService) GetPlaylist(ctx context.Context, req *up.GetPlaylistRequest) (*up.GetPlaylistResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	playlist, err := s.findPlaylist(ctx, req.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.toPlaylistWithItems(ctx, playlist, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.GetPlaylistResponse{
+		Playlist: *p,
+	}, nil
+}
+
+// ListPlaylists lists playlists newest first, without their items
+func (s *PlaylistService) ListPlaylists(ctx context.Context, req *up.ListPlaylistsRequest) (*up.ListPlaylistsResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	limit := defaultPlaylistsLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+
+	args := &repositories.ListPlaylistsArgs{
+		ViewerID: userID,
+		// fetch one more playlist to know if there is a next page
+		Limit: limit + 1,
+	}
+	if req.UserID != "" {
+		args.UserID = &req.UserID
+	}
+	if req.Cursor != "" {
+		after, err := cursor.Decode(req.Cursor)
+		if err != nil {
+			return nil, xerror.ErrorM(xerror.InvalidArgument, err, "invalid cursor")
+		}
+		args.After = after
+	}
+
+	playlists, err := s.playlistRepo.List(ctx, args)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.playlistRepo.List: %w", err))
+	}
+
+	resp := &up.ListPlaylistsResponse{}
+	if len(playlists) > limit {
+		playlists = playlists[:limit]
+		last := playlists[limit-1]
+		resp.NextCursor = cursor.Encode(&cursor.Cursor{CreatedAt: *last.CreatedAt, ID: last.ID})
+	}
+	if len(playlists) == 0 {
+		return resp, nil
+	}
+
+	resp.Playlists, err = s.toPlaylists(ctx, playlists)
+	if err != nil {
+		return nil, err
+	}
+
+	playlistIDs := make([]string, 0, len(playlists))
+	for _, playlist := range playlists {
+		playlistIDs = append(playlistIDs, playlist.ID)
+	}
+	counts, err := s.itemRepo.CountByPlaylistIDs(ctx, playlistIDs)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.itemRepo.CountByPlaylistIDs: %w", err))
+	}
+	for _, playlist := range resp.Playlists {
+		playlist.ItemCount = counts[playlist.ID]
+	}
+
+	return resp, nil
+}
+
+func (s *PlaylistService) UpdatePlaylist(ctx context.Context, req *up.UpdatePlaylistRequest) (*up.UpdatePlaylistResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	playlist, err := s.findOwnedPlaylist(ctx, req.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		playlist.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		playlist.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Visibility != nil && *req.Visibility != "" {
+		playlist.Visibility = *req.Visibility
+	}
+	now := time.Now()
+	playlist.UpdatedAt = &now
+
+	if err := s.playlistRepo.Update(ctx, playlist); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.playlistRepo.Update: %w", err))
+	}
+
+	p, err := s.toPlaylistWithItems(ctx, playlist, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.UpdatePlaylistResponse{
+		Playlist: *p,
+	}, nil
+}
+
+func (s *PlaylistService) DeletePlaylist(ctx context.Context, req *up.DeletePlaylistRequest) (*up.DeletePlaylistResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	if _, err := s.findOwnedPlaylist(ctx, req.ID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.playlistRepo.SoftDelete(ctx, req.ID, userID, time.Now()); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.playlistRepo.SoftDelete: %w", err))
+	}
+
+	return &up.DeletePlaylistResponse{}, nil
+}
+
+func (s *PlaylistService) AddPlaylistItem(ctx context.Context, req *up.AddPlaylistItemRequest) (*up.AddPlaylistItemResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	playlist, err := s.findOwnedPlaylist(ctx, req.PlaylistID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.movieRepo.FindByID(ctx, req.MovieID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", req.MovieID)
+	}
+
+	items, err := s.itemRepo.ListByPlaylistID(ctx, playlist.ID)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.itemRepo.ListByPlaylistID: %w", err))
+	}
+	if containsMovie(items, req.MovieID) {
+		return nil, xerror.ErrorMf(xerror.AlreadyExists, nil, "movie (%s) is already in the playlist", req.MovieID)
+	}
+	if len(items) >= up.MaxPlaylistItems {
+		return nil, xerror.ErrorMf(xerror.InvalidArgument, nil, "a playlist can't have more than %d movies", up.MaxPlaylistItems)
+	}
+
+	if err := s.itemRepo.Add(ctx, playlist.ID, req.MovieID, time.Now()); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.itemRepo.Add: %w", err))
+	}
+
+	p, err := s.toPlaylistWithItems(ctx, playlist, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.AddPlaylistItemResponse{
+		Playlist: *p,
+	}, nil
+}
+
+func (s *PlaylistService) RemovePlaylistItem(ctx context.Context, req *up.RemovePlaylistItemRequest) (*up.RemovePlaylistItemResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	playlist, err := s.findOwnedPlaylist(ctx, req.PlaylistID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.itemRepo.ListByPlaylistID(ctx, playlist.ID)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.itemRepo.ListByPlaylistID: %w", err))
+	}
+	if !containsMovie(items, req.MovieID) {
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) is not in the playlist", req.MovieID)
+	}
+
+	if err := s.itemRepo.Remove(ctx, playlist.ID, req.MovieID); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.itemRepo.Remove: %w", err))
+	}
+
+	p, err := s.toPlaylistWithItems(ctx, playlist, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.RemovePlaylistItemResponse{
+		Playlist: *p,
+	}, nil
+}
+
+// ReorderPlaylistItems plays the movies in the order of the request, deleted and hidden movies
+// which aren't returned to the client are moved after them
+func (s *PlaylistService) ReorderPlaylistItems(ctx context.Context, req *up.ReorderPlaylistItemsRequest) (*up.ReorderPlaylistItemsResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	playlist, err := s.findOwnedPlaylist(ctx, req.PlaylistID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, movies, err := s.listItems(ctx, playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[string]bool, len(movies))
+	for _, movie := range movies {
+		visible[movie.ID] = true
+	}
+	seen := make(map[string]bool, len(req.MovieIDs))
+	for _, movieID := range req.MovieIDs {
+		if !visible[movieID] || seen[movieID] {
+			return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "movie_ids must list every movie of the playlist once")
+		}
+		seen[movieID] = true
+	}
+	if len(seen) != len(visible) {
+		return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "movie_ids must list every movie of the playlist once")
+	}
+
+	order := append([]string{}, req.MovieIDs...)
+	for _, item := range items {
+		if !visible[item.MovieID] {
+			order = append(order, item.MovieID)
+		}
+	}
+
+	if len(order) > 0 {
+		if err := s.itemRepo.Reorder(ctx, playlist.ID, order); err != nil {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.itemRepo.Reorder: %w", err))
+		}
+	}
+
+	p, err := s.toPlaylistWithItems(ctx, playlist, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.ReorderPlaylistItemsResponse{
+		Playlist: *p,
+	}, nil
+}
+
+// findPlaylist finds a playlist visible to userID, private playlists of other users aren't found
+func (s *PlaylistService) findPlaylist(ctx context.Context, id, userID string) (*entities.Playlist, error) {
+	playlist, err := s.playlistRepo.FindByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.playlistRepo.FindByID: %w", err))
+		}
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "playlist (%s) not found", id)
+	}
+
+	if playlist.Visibility != entities.PlaylistPublic && playlist.UserID != userID {
+		return nil, xerror.ErrorMf(xerror.NotFound, nil, "playlist (%s) not found", id)
+	}
+
+	return playlist, nil
+}
+
+// findOwnedPlaylist finds a playlist which userID is allowed to mutate
+func (s *PlaylistService) findOwnedPlaylist(ctx context.Context, id, userID string) (*entities.Playlist, error) {
+	playlist, err := s.findPlaylist(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if playlist.UserID != userID {
+		return nil, xerror.ErrorM(xerror.PermissionDenied, nil, "only the owner can change the playlist")
+	}
+
+	return playlist, nil
+}
+
+// listItems returns every item of the playlist and their movies in play order.
+// Deleted and hidden movies stay in the playlist but have no movie, they aren't played.
+func (s *PlaylistService) listItems(ctx context.Context, playlistID string) (entities.PlaylistItems, entities.Movies, error) {
+	items, err := s.itemRepo.ListByPlaylistID(ctx, playlistID)
+	if err != nil {
+		return nil, nil, xerror.Error(xerror.Internal, fmt.Errorf("s.itemRepo.ListByPlaylistID: %w", err))
+	}
+	if len(items) == 0 {
+		return items, nil, nil
+	}
+
+	movieIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		movieIDs = append(movieIDs, item.MovieID)
+	}
+	movies, err := s.movieRepo.ListByIDs(ctx, movieIDs)
+	if err != nil {
+		return nil, nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.ListByIDs: %w", err))
+	}
+	movieMap := make(map[string]*entities.Movie)
+	for _, movie := range movies {
+		movieMap[movie.ID] = movie
+	}
+
+	ordered := make(entities.Movies, 0, len(movies))
+	for _, item := range items {
+		if movie, ok := movieMap[item.MovieID]; ok {
+			ordered = append(ordered, movie)
+		}
+	}
+
+	return items, ordered, nil
+}
+
+// toPlaylistWithItems converts a playlist to a response with its movies, as seen by userID
+func (s *PlaylistService) toPlaylistWithItems(ctx context.Context, playlist *entities.Playlist, userID string) (*up.Playlist, error) {
+	playlists, err := s.toPlaylists(ctx, entities.Playlists{playlist})
+	if err != nil {
+		return nil, err
+	}
+	p := playlists[0]
+
+	_, movies, err := s.listItems(ctx, playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.Items, err = s.movies.toMovies(ctx, movies, userID)
+	if err != nil {
+		return nil, err
+	}
+	p.ItemCount = len(p.Items)
+
+	return p, nil
+}
+
+// toPlaylists converts playlists to responses, looking up their owners in one query
+func (s *PlaylistService) toPlaylists(ctx context.Context, playlists entities.Playlists) ([]*up.Playlist, error) {
+	userIDs := make([]string, 0, len(playlists))
+	for _, playlist := range playlists {
+		userIDs = append(userIDs, playlist.UserID)
+	}
+
+	users, err := s.userRepo.List(ctx, &repositories.ListUsersArgs{IDs: userIDs})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.List: %w", err))
+	}
+	userMap := make(map[string]*entities.User)
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+
+	result := make([]*up.Playlist, 0, len(playlists))
+	for _, playlist := range playlists {
+		p := &up.Playlist{
+			ID:          playlist.ID,
+			UserID:      playlist.UserID,
+			Name:        playlist.Name,
+			Description: playlist.Description,
+			Visibility:  playlist.Visibility,
+			CreatedAt:   *playlist.CreatedAt,
+			UpdatedAt:   *playlist.UpdatedAt,
+		}
+		if user, ok := userMap[playlist.UserID]; ok {
+			p.Owner = user.Name
+		}
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+func containsMovie(items entities.PlaylistItems, movieID string) bool {
+	for _, item := range items {
+		if item.MovieID == movieID {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ViewPlaylistData struct {
+	URL         string
+	ID          string
+	Name        string
+	Description string
+	Owner       string
+	Items       []*ViewPlaylistItem
+}
+
+// ViewPlaylistItem Link is the embed URL of the movie
+type ViewPlaylistItem struct {
+	ID        string
+	Name      string
+	Link      string
+	Thumbnail string
+	SharedBy  string
+}
+
+// GetViewPlaylistPage plays the movies of a public playlist one after another
+func (s *PlaylistService) GetViewPlaylistPage(w http.ResponseWriter, r *http.Request) {
+	tmpl := template.Must(template.ParseFiles("templates/playlist.html", "templates/session.html"))
+
+	ctx := context.Background()
+
+	// pages are served without a token, so only public playlists can be viewed
+	playlist, err := s.playlistRepo.FindByID(ctx, PathParam(r, "id"))
+	if err != nil || playlist.Visibility != entities.PlaylistPublic {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	p, err := s.toPlaylistWithItems(ctx, playlist, "")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	viewPlaylistData := ViewPlaylistData{
+		URL:         s.url,
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Owner:       p.Owner,
+	}
+	for _, movie := range p.Items {
+		video, err := s.movies.resolver.Resolve(movie.Link)
+		if err != nil {
+			continue
+		}
+		viewPlaylistData.Items = append(viewPlaylistData.Items, &ViewPlaylistItem{
+			ID:        movie.ID,
+			Name:      movie.Name,
+			Link:      video.EmbedURL,
+			Thumbnail: movie.Thumbnail,
+			SharedBy:  movie.SharedBy,
+		})
+	}
+
+	tmpl.Execute(w, viewPlaylistData)
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"remi/internal/entities"
+	"remi/pkg/blobstore"
+	"remi/pkg/golibs/database"
+	"remi/pkg/golibs/idutil"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+// avatarContentTypes maps the extensions of avatar keys to the content types accepted on upload
+var avatarContentTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+func (s *UserService) GetProfile(ctx context.Context, req *up.GetProfileRequest) (*up.GetProfileResponse, error) {
+	user, err := s.findCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.GetProfileResponse{
+		Profile: *s.toProfile(user),
+	}, nil
+}
+
+func (s *UserService) UpdateProfile(ctx context.Context, req *up.UpdateProfileRequest) (*up.UpdateProfileResponse, error) {
+	user, err := s.findCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		user.Name = *req.Name
+		if err := s.userRepo.UpdateName(ctx, user.ID, user.Name, time.Now()); err != nil {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.UpdateName: %w", err))
+		}
+	}
+
+	return &up.UpdateProfileResponse{
+		Profile: *s.toProfile(user),
+	}, nil
+}
+
+// ChangePassword is throttled like Login since it also verifies a password
+func (s *UserService) ChangePassword(ctx context.Context, req *up.ChangePasswordRequest) (*up.ChangePasswordResponse, error) {
+	user, err := s.findCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, _ := clientIPFromCtx(ctx)
+	if err := s.loginLimiter.check(ctx, user.Username, ip); err != nil {
+		return nil, err
+	}
+	ok, err := s.passwordHasher.Verify(req.CurrentPassword, user.Password)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.passwordHasher.Verify: %w", err))
+	}
+	if !ok {
+		if err := s.loginLimiter.fail(ctx, user.Username, ip); err != nil {
+			return nil, err
+		}
+		return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "current_password is incorrect").
+			WithDetails(xerror.FieldViolation{Field: "current_password", Description: "is incorrect"})
+	}
+	if err := s.loginLimiter.succeed(ctx, user.Username); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPasswordPolicy("new_password", req.NewPassword); err != nil {
+		return nil, err
+	}
+	hash, err := s.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.passwordHasher.Hash: %w", err))
+	}
+
+	// the other sessions are logged out along with the password change or not at all
+	var refreshToken string
+	now := time.Now()
+	err = database.ExecInTx(ctx, s.db, func(tx *sql.Tx) error {
+		userRepo := s.userRepo.WithTx(tx)
+		if err := userRepo.UpdatePassword(ctx, user.ID, user.Password, hash, now); err != nil {
+			return fmt.Errorf("s.userRepo.UpdatePassword: %w", err)
+		}
+
+		// access tokens of other sessions can't be listed, they are rejected by their issue time
+		if err := userRepo.InvalidateTokens(ctx, user.ID, now); err != nil {
+			return fmt.Errorf("s.userRepo.InvalidateTokens: %w", err)
+		}
+		refreshTokenRepo := s.refreshTokenRepo.WithTx(tx)
+		if err := refreshTokenRepo.RevokeByUserID(ctx, user.ID, now); err != nil {
+			return fmt.Errorf("s.refreshTokenRepo.RevokeByUserID: %w", err)
+		}
+		token, _ := accessTokenFromCtx(ctx)
+		err := s.revokedTokenRepo.WithTx(tx).Create(ctx, &entities.RevokedToken{
+			JTI:       token.JTI,
+			ExpiresAt: &token.ExpiresAt,
+		})
+		if err != nil {
+			return fmt.Errorf("s.revokedTokenRepo.Create: %w", err)
+		}
+
+		refreshToken, err = s.createRefreshToken(ctx, refreshTokenRepo, user.ID, idutil.NewID(), idutil.NewID())
+		return err
+	})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, err)
+	}
+
+	// issued after the invalidation so that it isn't rejected
+	newToken, err := s.createToken(user.ID, user.Username, user.Role)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, err)
+	}
+
+	return &up.ChangePasswordResponse{
+		Token:        newToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// UploadAvatar stores the image under a new key so that avatar URLs can be cached forever,
+// the previous avatar is deleted
+func (s *UserService) UploadAvatar(ctx context.Context, req *up.UploadAvatarRequest) (*up.UploadAvatarResponse, error) {
+	user, err := s.findCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(req.Image)
+	ext := ""
+	for e, t := range avatarContentTypes {
+		if t == contentType {
+			ext = e
+		}
+	}
+	if ext == "" {
+		return nil, xerror.ErrorM(xerror.InvalidArgument, nil, "image must be a PNG, JPEG, GIF or WebP image").
+			WithDetails(xerror.FieldViolation{Field: "image", Description: "must be a PNG, JPEG, GIF or WebP image"})
+	}
+
+	key := user.ID + "-" + idutil.NewID() + ext
+	if err := s.blobs.Put(ctx, key, bytes.NewReader(req.Image)); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.blobs.Put: %w", err))
+	}
+
+	if err := s.replaceAvatar(ctx, user, &key); err != nil {
+		return nil, err
+	}
+
+	return &up.UploadAvatarResponse{
+		Profile: *s.toProfile(user),
+	}, nil
+}
+
+func (s *UserService) DeleteAvatar(ctx context.Context, req *up.DeleteAvatarRequest) (*up.DeleteAvatarResponse, error) {
+	user, err := s.findCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.AvatarKey != nil {
+		if err := s.replaceAvatar(ctx, user, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &up.DeleteAvatarResponse{
+		Profile: *s.toProfile(user),
+	}, nil
+}
+
+// replaceAvatar sets the avatar key of the user, failing to delete the previous blob only leaves an orphan
+func (s *UserService) replaceAvatar(ctx context.Context, user *entities.User, key *string) error {
+	if err := s.userRepo.SetAvatar(ctx, user.ID, key, time.Now()); err != nil {
+		return xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.SetAvatar: %w", err))
+	}
+
+	if user.AvatarKey != nil {
+		if err := s.blobs.Delete(ctx, *user.AvatarKey); err != nil {
+			log.Printf("s.blobs.Delete: %v", err)
+		}
+	}
+	user.AvatarKey = key
+
+	return nil
+}
+
+// GetAvatar serves /avatars/{key}
+func (s *UserService) GetAvatar(w http.ResponseWriter, r *http.Request) {
+	key := PathParam(r, "key")
+	contentType, ok := avatarContentTypes[path.Ext(key)]
+	if !ok {
+		writeError(w, xerror.ErrorM(xerror.NotFound, nil, "avatar not found"))
+		return
+	}
+
+	blob, err := s.blobs.Open(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) || errors.Is(err, blobstore.ErrInvalidKey) {
+			writeError(w, xerror.ErrorM(xerror.NotFound, nil, "avatar not found"))
+			return
+		}
+		writeError(w, xerror.Error(xerror.Internal, fmt.Errorf("s.blobs.Open: %w", err)))
+		return
+	}
+	defer blob.Close()
+
+	// keys are never reused, a new upload gets a new URL
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, blob); err != nil {
+		log.Printf("io.Copy: %v", err)
+	}
+}
+
+// findCaller finds the user of the access token
+func (s *UserService) findCaller(ctx context.Context) (*entities.User, error) {
+	userID, _ := userIDFromCtx(ctx)
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByID: %w", err))
+	}
+
+	return user, nil
+}
+
+func (s *UserService) toProfile(user *entities.User) *up.Profile {
+	return &up.Profile{
+		ID:        user.ID,
+		Username:  user.Username,
+		Name:      user.Name,
+		Role:      user.Role,
+		AvatarURL: avatarURL(s.url, user.AvatarKey),
+		CreatedAt: *user.CreatedAt,
+	}
+}
+
+// avatarURL returns the URL serving the avatar, or an empty string when there is no avatar
+func avatarURL(baseURL string, key *string) string {
+	if key == nil {
+		return ""
+	}
+	return baseURL + "/avatars/" + *key
+}
@@ -0,0 +1,45 @@
+package services
+
+import "remi/internal/entities"
+
+// Permission is an action which isn't granted to every user
+type Permission string
+
+const (
+	PermissionHideMovies  = Permission("movies:hide")
+	PermissionViewReports = Permission("reports:view")
+	PermissionListUsers   = Permission("users:list")
+	PermissionBanUsers    = Permission("users:ban")
+	PermissionManageRoles = Permission("users:manage_roles")
+)
+
+var rolePermissions = map[string][]Permission{
+	entities.RoleUser: {},
+	entities.RoleModerator: {
+		PermissionHideMovies,
+		PermissionViewReports,
+		PermissionListUsers,
+		PermissionBanUsers,
+	},
+	entities.RoleAdmin: {
+		PermissionHideMovies,
+		PermissionViewReports,
+		PermissionListUsers,
+		PermissionBanUsers,
+		PermissionManageRoles,
+	},
+}
+
+func isValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func hasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
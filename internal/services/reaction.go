@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"remi/internal/entities"
+	"remi/internal/repositories"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+var _ up.ReactionService = &ReactionService{}
+
+type ReactionService struct {
+	reactionRepo *repositories.ReactionRepository
+	movieRepo    *repositories.MovieRepository
+}
+
+func NewReactionService(db *sql.DB) *ReactionService {
+	return &ReactionService{
+		reactionRepo: repositories.NewReactionRepository(db),
+		movieRepo:    repositories.NewMovieRepository(db),
+	}
+}
+
+func (s *ReactionService) LikeMovie(ctx context.Context, req *up.LikeMovieRequest) (*up.LikeMovieResponse, error) {
+	reactions, err := s.vote(ctx, req.MovieID, entities.ReactionLike)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.LikeMovieResponse{
+		MovieReactions: *reactions,
+	}, nil
+}
+
+func (s *ReactionService) DislikeMovie(ctx context.Context, req *up.DislikeMovieRequest) (*up.DislikeMovieResponse, error) {
+	reactions, err := s.vote(ctx, req.MovieID, entities.ReactionDislike)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.DislikeMovieResponse{
+		MovieReactions: *reactions,
+	}, nil
+}
+
+func (s *ReactionService) UnvoteMovie(ctx context.Context, req *up.UnvoteMovieRequest) (*up.UnvoteMovieResponse, error) {
+	if err := s.checkMovieExists(ctx, req.MovieID); err != nil {
+		return nil, err
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	if err := s.reactionRepo.Delete(ctx, req.MovieID, userID); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.reactionRepo.Delete: %w", err))
+	}
+
+	reactions, err := s.summary(ctx, req.MovieID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.UnvoteMovieResponse{
+		MovieReactions: *reactions,
+	}, nil
+}
+
+// vote records the vote of the caller, replacing the previous one
+func (s *ReactionService) vote(ctx context.Context, movieID, reactionType string) (*up.MovieReactions, error) {
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	now := time.Now()
+	err := s.reactionRepo.Upsert(ctx, &entities.Reaction{
+		MovieID:   movieID,
+		UserID:    userID,
+		Type:      reactionType,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.reactionRepo.Upsert: %w", err))
+	}
+
+	return s.summary(ctx, movieID, userID)
+}
+
+func (s *ReactionService) checkMovieExists(ctx context.Context, movieID string) error {
+	if _, err := s.movieRepo.FindByID(ctx, movieID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.FindByID: %w", err))
+		}
+		return xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", movieID)
+	}
+
+	return nil
+}
+
+func (s *ReactionService) summary(ctx context.Context, movieID, userID string) (*up.MovieReactions, error) {
+	movies := []*up.Movie{{ID: movieID}}
+	if err := fillReactions(ctx, s.reactionRepo, movies, userID); err != nil {
+		return nil, err
+	}
+
+	return &up.MovieReactions{
+		MovieID:  movieID,
+		Likes:    movies[0].Likes,
+		Dislikes: movies[0].Dislikes,
+		MyVote:   movies[0].MyVote,
+	}, nil
+}
+
+// fillReactions sets vote counts and the vote of userID on the movies using one query for each
+func fillReactions(ctx context.Context, reactionRepo *repositories.ReactionRepository, movies []*up.Movie, userID string) error {
+	if len(movies) == 0 {
+		return nil
+	}
+
+	movieIDs := make([]string, 0, len(movies))
+	for _, movie := range movies {
+		movieIDs = append(movieIDs, movie.ID)
+	}
+
+	counts, err := reactionRepo.CountByMovieIDs(ctx, movieIDs)
+	if err != nil {
+		return xerror.Error(xerror.Internal, fmt.Errorf("reactionRepo.CountByMovieIDs: %w", err))
+	}
+
+	myReactions := make(map[string]*entities.Reaction)
+	if userID != "" {
+		myReactions, err = reactionRepo.ListByUserAndMovieIDs(ctx, userID, movieIDs)
+		if err != nil {
+			return xerror.Error(xerror.Internal, fmt.Errorf("reactionRepo.ListByUserAndMovieIDs: %w", err))
+		}
+	}
+
+	for _, movie := range movies {
+		if count, ok := counts[movie.ID]; ok {
+			movie.Likes = count.Likes
+			movie.Dislikes = count.Dislikes
+		}
+		if reaction, ok := myReactions[movie.ID]; ok {
+			movie.MyVote = reaction.Type
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"remi/pkg/validator"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+// Validator is implemented by requests with checks which can't be written as validate tags,
+// Handle validates them before calling the handler
+type Validator interface {
+	Validate() error
+}
+
+// Route describes how a path is served, a Permission implies the User auth type.
+// Path segments written as {name} match any value, which is bound to the request
+// field tagged with path:"name".
+type Route struct {
+	Method     string
+	Path       string
+	Auth       AuthType
+	Permission Permission
+	// TokenInQuery also accepts the access token in the token query param, only for clients which can't
+	// set headers such as EventSource, since URLs end up in logs and browser history
+	TokenInQuery bool
+}
+
+type routeHandler struct {
+	Route
+	// service, name, request and response are only set for JSON endpoints, they describe the API
+	service  string
+	name     string
+	request  reflect.Type
+	response reflect.Type
+	serve    func(resp http.ResponseWriter, req *http.Request)
+}
+
+// Router dispatches requests to handlers registered with Handle and HandleHTTP
+type Router struct {
+	// routes are keyed by path then method
+	routes map[string]map[string]*routeHandler
+	// patterns are the paths with parameters, in registration order
+	patterns [][]string
+}
+
+func NewRouter() *Router {
+	return &Router{
+		routes: make(map[string]map[string]*routeHandler),
+	}
+}
+
+// maxRequestBodySize leaves room for base64 encoded uploads such as avatars
+const maxRequestBodySize = 4 << 20
+
+type pathParamsKey struct{}
+
+// PathParam returns the value of the {name} segment of the route which matched the request
+func PathParam(req *http.Request, name string) string {
+	params, _ := req.Context().Value(pathParamsKey{}).(map[string]string)
+	return params[name]
+}
+
+// Handle registers a JSON endpoint. The body is decoded into Req, validated with its validate tags
+// and then with Validate when Req implements Validator, and the response or the error returned by
+// the handler is encoded as JSON.
+func Handle[Req, Resp any](r *Router, route Route, handler func(context.Context, *Req) (*Resp, error)) {
+	reqType := reflect.TypeOf((*Req)(nil)).Elem()
+	if reqType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("%s %s: request must be a struct, got %s", route.Method, route.Path, reqType))
+	}
+	// parses the validate tags now so that invalid ones panic on startup
+	_ = validator.Validate(new(Req))
+
+	h := r.add(route, func(resp http.ResponseWriter, req *http.Request) {
+		args := new(Req)
+		req.Body = http.MaxBytesReader(resp, req.Body, maxRequestBodySize)
+		if err := decodeRequest(req, args); err != nil {
+			writeError(resp, err)
+			return
+		}
+		if err := bindPathParams(req, args); err != nil {
+			writeError(resp, xerror.ErrorMf(xerror.InvalidArgument, err, "invalid path parameter %v", err))
+			return
+		}
+
+		if err := validator.Validate(args); err != nil {
+			writeError(resp, err)
+			return
+		}
+		if v, ok := interface{}(args).(Validator); ok {
+			if err := v.Validate(); err != nil {
+				writeError(resp, err)
+				return
+			}
+		}
+
+		result, err := handler(req.Context(), args)
+		if err != nil {
+			writeError(resp, err)
+			return
+		}
+
+		resp.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(resp).Encode(result)
+	})
+	h.service, h.name = handlerName(handler)
+	h.request = reqType
+	h.response = reflect.TypeOf((*Resp)(nil)).Elem()
+}
+
+// HandleHTTP registers a handler which writes the response itself, e.g. pages and streams
+func HandleHTTP(r *Router, route Route, handler http.HandlerFunc) {
+	r.add(route, handler)
+}
+
+// add panics on invalid routes so mistakes are caught when the server starts
+func (r *Router) add(route Route, serve func(http.ResponseWriter, *http.Request)) *routeHandler {
+	if route.Method == "" || route.Path == "" {
+		panic(fmt.Sprintf("route %q %q: method and path are required", route.Method, route.Path))
+	}
+	if route.Permission != "" && route.Auth != User {
+		panic(fmt.Sprintf("%s %s: a permission requires the User auth type", route.Method, route.Path))
+	}
+
+	methods, ok := r.routes[route.Path]
+	if !ok {
+		methods = make(map[string]*routeHandler)
+		r.routes[route.Path] = methods
+		if strings.Contains(route.Path, "{") {
+			r.patterns = append(r.patterns, strings.Split(route.Path, "/"))
+		}
+	}
+	if _, ok := methods[route.Method]; ok {
+		panic(fmt.Sprintf("%s %s: route is registered twice", route.Method, route.Path))
+	}
+
+	h := &routeHandler{
+		Route: route,
+		serve: serve,
+	}
+	methods[route.Method] = h
+
+	return h
+}
+
+// handlerName returns the receiver and the method a handler is bound to,
+// e.g. MovieService and ListMovies for movieService.ListMovies
+func handlerName(handler interface{}) (receiver, method string) {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+
+	i := strings.LastIndex(name, ".")
+	method = name[i+1:]
+	receiver = strings.Trim(name[strings.LastIndex(name[:i], ".")+1:i], "(*)")
+	return receiver, method
+}
+
+// lookup finds the handler of the request, exact paths take precedence over paths with parameters.
+// When the path matches but the method doesn't, the methods allowed for the path are returned.
+func (r *Router) lookup(method, path string) (_ *routeHandler, params map[string]string, allowed []string) {
+	methods, ok := r.routes[path]
+	if !ok {
+		methods, params, ok = r.match(path)
+	}
+	if !ok {
+		return nil, nil, nil
+	}
+
+	h, ok := methods[method]
+	if !ok {
+		for m := range methods {
+			allowed = append(allowed, m)
+		}
+		sort.Strings(allowed)
+		return nil, nil, allowed
+	}
+
+	return h, params, nil
+}
+
+func (r *Router) match(path string) (map[string]*routeHandler, map[string]string, bool) {
+	segments := strings.Split(path, "/")
+
+	for _, pattern := range r.patterns {
+		if len(pattern) != len(segments) {
+			continue
+		}
+
+		params := make(map[string]string)
+		matched := true
+		for i, p := range pattern {
+			if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+				if segments[i] == "" {
+					matched = false
+					break
+				}
+				params[p[1:len(p)-1]] = segments[i]
+				continue
+			}
+			if p != segments[i] {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return r.routes[strings.Join(pattern, "/")], params, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// decodeRequest reads the query params of GET requests into the fields with a json tag
+// and the JSON body of the other methods, an empty body leaves the request zero valued
+func decodeRequest(req *http.Request, args interface{}) error {
+	switch req.Method {
+	case http.MethodGet:
+		query := req.URL.Query()
+		err := bindFields(args, "json", func(name string) ([]string, bool) {
+			values, ok := query[name]
+			return values, ok
+		})
+		if err != nil {
+			return xerror.ErrorMf(xerror.InvalidArgument, err, "invalid query parameter %v", err)
+		}
+	default:
+		defer req.Body.Close()
+		if err := json.NewDecoder(req.Body).Decode(args); err != nil && !errors.Is(err, io.EOF) {
+			return xerror.ErrorM(xerror.InvalidArgument, err, "invalid request body")
+		}
+	}
+
+	return nil
+}
+
+func bindPathParams(req *http.Request, args interface{}) error {
+	params, _ := req.Context().Value(pathParamsKey{}).(map[string]string)
+	if len(params) == 0 {
+		return nil
+	}
+
+	return bindFields(args, "path", func(name string) ([]string, bool) {
+		v, ok := params[name]
+		return []string{v}, ok
+	})
+}
+
+// bindFields sets the fields of args, a pointer to a struct, named by tag from the values returned by lookup.
+// Only strings, integers and booleans, pointers to them and slices of strings are supported. Slices take
+// every value, e.g. ?tags=a&tags=b, the other fields the first one.
+func bindFields(args interface{}, tag string, lookup func(name string) ([]string, bool)) error {
+	v := reflect.ValueOf(args).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		values, ok := lookup(name)
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.String {
+			v.Field(i).Set(reflect.ValueOf(values).Convert(field.Type))
+			continue
+		}
+		if err := setField(v.Field(i), values[0]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setField(ptr.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
+
+// writeError encodes err as the JSON error response. Errors which aren't an xerror.XError are reported
+// as internal errors, causes are logged and never sent.
+func writeError(resp http.ResponseWriter, err error) {
+	var xErr xerror.XError
+	if !errors.As(err, &xErr) {
+		xErr = xerror.Error(xerror.Internal, err)
+	}
+	if xErr.HttpStatus() >= http.StatusInternalServerError {
+		log.Println(err)
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(xErr.HttpStatus())
+	json.NewEncoder(resp).Encode(up.ErrorResponse{
+		Error:   xErr.Message,
+		Code:    xErr.Code.String(),
+		Details: xErr.Details,
+	})
+}
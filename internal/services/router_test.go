@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"remi/pkg/xerror"
+	"remi/up"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRouterRequest struct {
+	Name  string `json:"name" validate:"required,max=10"`
+	Email string `json:"email"`
+}
+
+// Validate rejects names which are also the email, a check validate tags can't express
+func (r *testRouterRequest) Validate() error {
+	if r.Email != "" && r.Name == r.Email {
+		return xerror.ErrorM(xerror.InvalidArgument, nil, "name can't be the email").
+			WithDetails(xerror.FieldViolation{Field: "name", Description: "can't be the email"})
+	}
+	return nil
+}
+
+type testRouterResponse struct {
+	Name string `json:"name"`
+}
+
+func echoName(_ context.Context, req *testRouterRequest) (*testRouterResponse, error) {
+	return &testRouterResponse{Name: req.Name}, nil
+}
+
+// newTestRemiService serves the routes registered by register, without a database
+func newTestRemiService(register func(r *Router)) *RemiService {
+	s := &RemiService{router: NewRouter()}
+	register(s.router)
+	return s
+}
+
+func serve(s *RemiService, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	s.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestHandle(t *testing.T) {
+	s := newTestRemiService(func(r *Router) {
+		Handle(r, Route{Method: http.MethodPost, Path: "/echo"}, echoName)
+	})
+
+	testCases := []struct {
+		name            string
+		body            string
+		expectedStatus  int
+		expectedResp    *testRouterResponse
+		expectedDetails []xerror.FieldViolation
+	}{
+		{
+			name:           "happy case",
+			body:           `{"name": "remi"}`,
+			expectedStatus: http.StatusOK,
+			expectedResp:   &testRouterResponse{Name: "remi"},
+		},
+		{
+			name:           "bad JSON",
+			body:           `{"name": `,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "wrong JSON type",
+			body:           `{"name": 42}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:            "empty body fails the validate tags",
+			body:            ``,
+			expectedStatus:  http.StatusBadRequest,
+			expectedDetails: []xerror.FieldViolation{{Field: "name", Description: "can't be null"}},
+		},
+		{
+			name:            "validate tag failure",
+			body:            `{"name": "a name longer than 10"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedDetails: []xerror.FieldViolation{{Field: "name", Description: "can't be longer than 10 characters"}},
+		},
+		{
+			name:            "Validate failure",
+			body:            `{"name": "remi", "email": "remi"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedDetails: []xerror.FieldViolation{{Field: "name", Description: "can't be the email"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resp := serve(s, http.MethodPost, "/echo", testCase.body)
+			assert.Equal(t, testCase.expectedStatus, resp.Code)
+			assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+
+			if testCase.expectedResp != nil {
+				actual := &testRouterResponse{}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(actual))
+				assert.Equal(t, testCase.expectedResp, actual)
+				return
+			}
+
+			errResp := &up.ErrorResponse{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(errResp))
+			assert.Equal(t, xerror.InvalidArgument.String(), errResp.Code)
+			assert.NotEmpty(t, errResp.Error)
+			assert.Equal(t, testCase.expectedDetails, errResp.Details)
+		})
+	}
+}
+
+func TestHandle_Panics(t *testing.T) {
+	testCases := []struct {
+		name     string
+		register func(r *Router)
+	}{
+		{
+			name: "request isn't a struct",
+			register: func(r *Router) {
+				Handle(r, Route{Method: http.MethodGet, Path: "/names"}, func(context.Context, *string) (*testRouterResponse, error) {
+					return nil, nil
+				})
+			},
+		},
+		{
+			name: "duplicate route",
+			register: func(r *Router) {
+				Handle(r, Route{Method: http.MethodPost, Path: "/echo"}, echoName)
+				Handle(r, Route{Method: http.MethodPost, Path: "/echo"}, echoName)
+			},
+		},
+		{
+			name: "permission without the User auth type",
+			register: func(r *Router) {
+				Handle(r, Route{Method: http.MethodPost, Path: "/echo", Auth: OptionalUser, Permission: PermissionBanUsers}, echoName)
+			},
+		},
+		{
+			name: "missing path",
+			register: func(r *Router) {
+				Handle(r, Route{Method: http.MethodPost}, echoName)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Panics(t, func() {
+				testCase.register(NewRouter())
+			})
+		})
+	}
+}
+
+type testPathRequest struct {
+	ID   string `json:"-" path:"id"`
+	Page *int   `json:"-" path:"page"`
+}
+
+type testPathResponse struct {
+	Route string `json:"route"`
+	ID    string `json:"id"`
+	Page  *int   `json:"page"`
+}
+
+func TestRemiService_ServeHTTP_Routing(t *testing.T) {
+	handler := func(route string) func(context.Context, *testPathRequest) (*testPathResponse, error) {
+		return func(_ context.Context, req *testPathRequest) (*testPathResponse, error) {
+			return &testPathResponse{Route: route, ID: req.ID, Page: req.Page}, nil
+		}
+	}
+	// the route with a parameter is registered first, the exact path must still win
+	s := newTestRemiService(func(r *Router) {
+		Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/movies/{id}"}, handler("get"))
+		Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/movies/{id}"}, handler("put"))
+		Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/movies/{id}"}, handler("delete"))
+		Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/movies/search"}, handler("search"))
+		Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/movies/{id}/comments/{page}"}, handler("comments"))
+	})
+	page := 2
+
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		expectedStatus int
+		expectedResp   *testPathResponse
+		expectedCode   xerror.Code
+		expectedAllow  string
+	}{
+		{
+			name:           "path param",
+			method:         http.MethodGet,
+			target:         "/api/v2/movies/movie-1",
+			expectedStatus: http.StatusOK,
+			expectedResp:   &testPathResponse{Route: "get", ID: "movie-1"},
+		},
+		{
+			name:           "path param of another method",
+			method:         http.MethodDelete,
+			target:         "/api/v2/movies/movie-1",
+			expectedStatus: http.StatusOK,
+			expectedResp:   &testPathResponse{Route: "delete", ID: "movie-1"},
+		},
+		{
+			name:           "exact path wins over path param",
+			method:         http.MethodGet,
+			target:         "/api/v2/movies/search",
+			expectedStatus: http.StatusOK,
+			expectedResp:   &testPathResponse{Route: "search"},
+		},
+		{
+			name:           "integer path param",
+			method:         http.MethodGet,
+			target:         "/api/v2/movies/movie-1/comments/2",
+			expectedStatus: http.StatusOK,
+			expectedResp:   &testPathResponse{Route: "comments", ID: "movie-1", Page: &page},
+		},
+		{
+			name:           "bad integer path param",
+			method:         http.MethodGet,
+			target:         "/api/v2/movies/movie-1/comments/two",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   xerror.InvalidArgument,
+		},
+		{
+			name:           "unknown method",
+			method:         http.MethodPatch,
+			target:         "/api/v2/movies/movie-1",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedCode:   xerror.MethodNotAllowed,
+			expectedAllow:  "DELETE, GET, PUT",
+		},
+		{
+			name:           "unknown path",
+			method:         http.MethodGet,
+			target:         "/api/v2/shows/show-1",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   xerror.NotFound,
+		},
+		{
+			name:           "empty path param",
+			method:         http.MethodGet,
+			target:         "/api/v2/movies/",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   xerror.NotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resp := serve(s, testCase.method, testCase.target, "")
+			assert.Equal(t, testCase.expectedStatus, resp.Code)
+			assert.Equal(t, testCase.expectedAllow, resp.Header().Get("Allow"))
+
+			if testCase.expectedResp != nil {
+				actual := &testPathResponse{}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(actual))
+				assert.Equal(t, testCase.expectedResp, actual)
+				return
+			}
+			if testCase.expectedCode != 0 {
+				errResp := &up.ErrorResponse{}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(errResp))
+				assert.Equal(t, testCase.expectedCode.String(), errResp.Code)
+			}
+		})
+	}
+}
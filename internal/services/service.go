@@ -3,131 +3,189 @@ package services
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"log"
+	"math"
+	"net"
 	"net/http"
-	"reflect"
+	"strings"
+	"time"
 
+	"remi/internal/entities"
+	"remi/internal/repositories"
+	"remi/pkg/blobstore"
+	"remi/pkg/config"
+	"remi/pkg/videoprovider"
 	"remi/pkg/xerror"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
-type (
-	AuthType     int
-	ResponseType int
-)
+type AuthType int
 
 const (
 	None = AuthType(0)
 	User = AuthType(1)
-
-	JSON = ResponseType(0)
-	HTML = ResponseType(1)
-	File = ResponseType(2)
+	// OptionalUser identifies the user when a valid token is sent but also serves anonymous requests
+	OptionalUser = AuthType(2)
 )
 
-type Decl struct {
-	Auth         AuthType
-	HandlerFunc  interface{}
-	ResponseType ResponseType
-}
-
 type RemiService struct {
-	jwtKey       string
-	userService  *UserService
-	movieService *MovieService
-	acl          map[string]map[string]Decl
+	jwtKey           string
+	userRepo         *repositories.UserRepository
+	revokedTokenRepo *repositories.RevokedTokenRepository
+	userService      *UserService
+	movieService     *MovieService
+	reactionService  *ReactionService
+	commentService   *CommentService
+	tagService       *TagService
+	followService    *FollowService
+	watchService     *WatchService
+	playlistService  *PlaylistService
+	adminService     *AdminService
+	hub              *NotificationHub
+	router           *Router
+	url              string
+	clientIPHeader   string
 }
 
-func NewRemiService(db *sql.DB, JWTKey, url string) *RemiService {
-	userService := NewUserService(db, JWTKey, url)
-	movieService := NewMovieService(db, url)
-
-	return &RemiService{
-		jwtKey:       JWTKey,
-		userService:  userService,
-		movieService: movieService,
-		acl: map[string]map[string]Decl{
-			"/api/v1/register": {
-				http.MethodPost: Decl{
-					HandlerFunc:  userService.Register,
-					Auth:         None,
-					ResponseType: JSON,
-				},
-			},
-			"/api/v1/login": {
-				http.MethodPost: Decl{
-					HandlerFunc:  userService.Login,
-					Auth:         None,
-					ResponseType: JSON,
-				},
-			},
-			"/api/v1/createMovie": {
-				http.MethodPost: Decl{
-					HandlerFunc:  movieService.Create,
-					Auth:         User,
-					ResponseType: JSON,
-				},
-			},
-			"/api/v1/getMovieByUser": {
-				http.MethodPost: Decl{
-					HandlerFunc:  movieService.GetMovieByUser,
-					Auth:         User,
-					ResponseType: JSON,
-				},
-			},
-			"/api/v1/listMoviesByUser": {
-				http.MethodPost: Decl{
-					HandlerFunc:  movieService.ListMoviesByUser,
-					Auth:         User,
-					ResponseType: JSON,
-				},
-			},
-			"/api/v1/listMovies": {
-				http.MethodPost: Decl{
-					HandlerFunc:  movieService.ListMovies,
-					Auth:         None,
-					ResponseType: JSON,
-				},
-			},
-			"/login": {
-				http.MethodGet: Decl{
-					HandlerFunc:  userService.GetLoginPage,
-					Auth:         None,
-					ResponseType: HTML,
-				},
-			},
-			"/register": {
-				http.MethodGet: Decl{
-					HandlerFunc:  userService.GetRegisterPage,
-					Auth:         None,
-					ResponseType: HTML,
-				},
-			},
-			"/": {
-				http.MethodGet: Decl{
-					HandlerFunc:  userService.GetHomePage,
-					Auth:         None,
-					ResponseType: HTML,
-				},
-			},
-			"/movies": {
-				http.MethodGet: Decl{
-					HandlerFunc:  movieService.GetCreateMoviePage,
-					Auth:         None,
-					ResponseType: HTML,
-				},
-			},
-			"/movie": {
-				http.MethodGet: Decl{
-					HandlerFunc:  movieService.GetViewMoviePage,
-					Auth:         None,
-					ResponseType: HTML,
-				},
-			},
-		},
+func NewRemiService(db *sql.DB, cfg *config.Config) *RemiService {
+	var metadata videoprovider.MetadataFetcher
+	if cfg.MetadataEnrichment {
+		metadata = videoprovider.NewOEmbedFetcher(
+			&http.Client{Timeout: cfg.MetadataTimeout},
+			videoprovider.DefaultResolver(),
+		)
+	}
+	hub := NewNotificationHub()
+	blobs := blobstore.NewFileStore(cfg.BlobDir)
+	commentService := NewCommentService(db)
+	movieService := NewMovieService(db, cfg.URL, hub, metadata, commentService)
+
+	s := &RemiService{
+		jwtKey:           cfg.JWTSecret,
+		userRepo:         repositories.NewUserRepository(db),
+		revokedTokenRepo: repositories.NewRevokedTokenRepository(db),
+		userService:      NewUserService(db, cfg, blobs),
+		movieService:     movieService,
+		reactionService:  NewReactionService(db),
+		commentService:   commentService,
+		tagService:       NewTagService(db),
+		followService:    NewFollowService(db, cfg.URL),
+		watchService:     NewWatchService(db, movieService),
+		playlistService:  NewPlaylistService(db, cfg.URL, movieService),
+		adminService:     NewAdminService(db),
+		hub:              hub,
+		router:           NewRouter(),
+		url:              cfg.URL,
+		clientIPHeader:   cfg.ClientIPHeader,
 	}
+	s.registerRoutes()
+
+	return s
+}
+
+func (s *RemiService) registerRoutes() {
+	r := s.router
+
+	// users
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/register", Auth: None}, s.userService.Register)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/login", Auth: None}, s.userService.Login)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/refresh", Auth: None}, s.userService.Refresh)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/logout", Auth: User}, s.userService.Logout)
+
+	// movies
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/createMovie", Auth: User}, s.movieService.Create)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/getMovieByUser", Auth: User}, s.movieService.GetMovieByUser)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/listMoviesByUser", Auth: User}, s.movieService.ListMoviesByUser)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/listMovies", Auth: OptionalUser}, s.movieService.ListMovies)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/searchMovies", Auth: OptionalUser}, s.movieService.SearchMovies)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/updateMovie", Auth: User}, s.movieService.UpdateMovie)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/deleteMovie", Auth: User}, s.movieService.DeleteMovie)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/reportMovie", Auth: User}, s.movieService.ReportMovie)
+
+	// reactions
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/likeMovie", Auth: User}, s.reactionService.LikeMovie)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/dislikeMovie", Auth: User}, s.reactionService.DislikeMovie)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/unvoteMovie", Auth: User}, s.reactionService.UnvoteMovie)
+
+	// comments
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/createComment", Auth: User}, s.commentService.CreateComment)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/editComment", Auth: User}, s.commentService.EditComment)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/deleteComment", Auth: User}, s.commentService.DeleteComment)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/listComments", Auth: None}, s.commentService.ListComments)
+
+	// moderation
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/listUsers", Auth: User, Permission: PermissionListUsers}, s.adminService.ListUsers)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/changeUserRole", Auth: User, Permission: PermissionManageRoles}, s.adminService.ChangeUserRole)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/disableUser", Auth: User, Permission: PermissionBanUsers}, s.adminService.DisableUser)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/enableUser", Auth: User, Permission: PermissionBanUsers}, s.adminService.EnableUser)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/hideMovie", Auth: User, Permission: PermissionHideMovies}, s.adminService.HideMovie)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/unhideMovie", Auth: User, Permission: PermissionHideMovies}, s.adminService.UnhideMovie)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v1/listReports", Auth: User, Permission: PermissionViewReports}, s.adminService.ListReports)
+
+	// v2 REST resources, the v1 routes above are kept for existing clients
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/me", Auth: User}, s.userService.GetProfile)
+	Handle(r, Route{Method: http.MethodPatch, Path: "/api/v2/me", Auth: User}, s.userService.UpdateProfile)
+	Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/me/password", Auth: User}, s.userService.ChangePassword)
+	Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/me/avatar", Auth: User}, s.userService.UploadAvatar)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/me/avatar", Auth: User}, s.userService.DeleteAvatar)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/me/watch-later", Auth: User}, s.watchService.ListWatchLater)
+	Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/me/watch-later/{movie_id}", Auth: User}, s.watchService.AddWatchLater)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/me/watch-later/{movie_id}", Auth: User}, s.watchService.RemoveWatchLater)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/me/history", Auth: User}, s.watchService.ListWatchHistory)
+	Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/me/history/{movie_id}", Auth: User}, s.watchService.RecordWatch)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/me/history/{movie_id}", Auth: User}, s.watchService.RemoveWatchHistory)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/movies", Auth: OptionalUser}, s.movieService.ListMovies)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v2/movies", Auth: User}, s.movieService.Create)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/feed", Auth: User}, s.movieService.ListFeed)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/u/{username}/movies", Auth: OptionalUser}, s.movieService.ListMoviesByUsername)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/movies/search", Auth: OptionalUser}, s.movieService.SearchMovies)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/tags", Auth: None}, s.tagService.ListTags)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/movies/{id}", Auth: OptionalUser}, s.movieService.GetMovie)
+	Handle(r, Route{Method: http.MethodPatch, Path: "/api/v2/movies/{id}", Auth: User}, s.movieService.UpdateMovie)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/movies/{id}", Auth: User}, s.movieService.DeleteMovie)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v2/movies/{movie_id}/reports", Auth: User}, s.movieService.ReportMovie)
+	Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/movies/{movie_id}/like", Auth: User}, s.reactionService.LikeMovie)
+	Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/movies/{movie_id}/dislike", Auth: User}, s.reactionService.DislikeMovie)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/movies/{movie_id}/vote", Auth: User}, s.reactionService.UnvoteMovie)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/movies/{movie_id}/comments", Auth: None}, s.commentService.ListComments)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v2/movies/{movie_id}/comments", Auth: User}, s.commentService.CreateComment)
+	Handle(r, Route{Method: http.MethodPatch, Path: "/api/v2/comments/{id}", Auth: User}, s.commentService.EditComment)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/comments/{id}", Auth: User}, s.commentService.DeleteComment)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/comments/{id}/replies", Auth: None}, s.commentService.ListReplies)
+	Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/users/{user_id}/follow", Auth: User}, s.followService.FollowUser)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/users/{user_id}/follow", Auth: User}, s.followService.UnfollowUser)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/users/{user_id}/followers", Auth: None}, s.followService.ListFollowers)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/users/{user_id}/following", Auth: None}, s.followService.ListFollowing)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/playlists", Auth: OptionalUser}, s.playlistService.ListPlaylists)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v2/playlists", Auth: User}, s.playlistService.CreatePlaylist)
+	Handle(r, Route{Method: http.MethodGet, Path: "/api/v2/playlists/{id}", Auth: OptionalUser}, s.playlistService.GetPlaylist)
+	Handle(r, Route{Method: http.MethodPatch, Path: "/api/v2/playlists/{id}", Auth: User}, s.playlistService.UpdatePlaylist)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/playlists/{id}", Auth: User}, s.playlistService.DeletePlaylist)
+	Handle(r, Route{Method: http.MethodPost, Path: "/api/v2/playlists/{playlist_id}/items", Auth: User}, s.playlistService.AddPlaylistItem)
+	Handle(r, Route{Method: http.MethodPut, Path: "/api/v2/playlists/{playlist_id}/items", Auth: User}, s.playlistService.ReorderPlaylistItems)
+	Handle(r, Route{Method: http.MethodDelete, Path: "/api/v2/playlists/{playlist_id}/items/{movie_id}", Auth: User}, s.playlistService.RemovePlaylistItem)
+
+	// documentation
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/api/openapi.json", Auth: None}, s.GetOpenAPI)
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/api/docs", Auth: None}, s.GetAPIDocsPage)
+
+	// uploads
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/avatars/{key}", Auth: None}, s.userService.GetAvatar)
+
+	// streams
+	// EventSource can't set headers, so the stream takes the token as a query param
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/api/v1/notifications", Auth: User, TokenInQuery: true}, s.streamNotifications)
+
+	// pages
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/login", Auth: None}, s.userService.GetLoginPage)
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/register", Auth: None}, s.userService.GetRegisterPage)
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/", Auth: None}, s.userService.GetHomePage)
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/movies", Auth: None}, s.movieService.GetCreateMoviePage)
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/movie", Auth: OptionalUser}, s.movieService.GetViewMoviePage)
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/movie/{id}", Auth: OptionalUser}, s.movieService.GetViewMoviePage)
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/playlist/{id}", Auth: None}, s.playlistService.GetViewPlaylistPage)
+	HandleHTTP(r, Route{Method: http.MethodGet, Path: "/u/{username}", Auth: None}, s.movieService.GetViewUserPage)
 }
 
 func (s *RemiService) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
@@ -141,90 +199,60 @@ func (s *RemiService) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	handler, ok := s.acl[req.URL.Path]
-	if !ok {
-		resp.WriteHeader(http.StatusNotFound)
+	handler, params, allowed := s.router.lookup(req.Method, req.URL.Path)
+	if handler == nil {
+		if len(allowed) > 0 {
+			resp.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeError(resp, xerror.ErrorMf(xerror.MethodNotAllowed, nil, "method %s not allowed", req.Method))
+			return
+		}
+		writeError(resp, xerror.ErrorM(xerror.NotFound, nil, "route not found"))
 		return
 	}
-
-	decl, ok := handler[req.Method]
-	if !ok {
-		resp.WriteHeader(http.StatusNotFound)
-		return
+	if len(params) > 0 {
+		req = req.WithContext(context.WithValue(req.Context(), pathParamsKey{}, params))
 	}
-
-	handlerFunc := decl.HandlerFunc
-	auth := decl.Auth
+	req = req.WithContext(context.WithValue(req.Context(), clientIPKey{}, s.clientIP(req)))
 
 	// authorization
-	switch auth {
+	switch handler.Auth {
 	case User:
 		var ok bool
-		req, ok = s.validToken(req)
+		req, ok = s.validToken(req, handler.TokenInQuery)
 		if !ok {
-			resp.WriteHeader(http.StatusUnauthorized)
+			writeError(resp, xerror.ErrorM(xerror.UnAuthorized, nil, "missing or invalid token"))
 			return
 		}
+	case OptionalUser:
+		req, _ = s.validToken(req, handler.TokenInQuery)
 	case None:
 		// no-op
 	}
 
-	switch decl.ResponseType {
-	case JSON:
-		// Call functions of services
-		// Todo: check function (type of arguments, outputs)
-		typ := reflect.TypeOf(handlerFunc)
-		funcArgs := reflect.New(typ.In(1).Elem()).Interface()
-
-		// parse body and request values
-		switch req.Method {
-		case http.MethodPost:
-			err := json.NewDecoder(req.Body).Decode(funcArgs)
-			defer req.Body.Close()
-			if err != nil {
-				log.Panicf("error %v", err)
-				return
-			}
-
-		case http.MethodGet:
-			funcArgsTyp := reflect.TypeOf(funcArgs).Elem()
-			for i := 0; i < funcArgsTyp.NumField(); i++ {
-				jsonTag := funcArgsTyp.Field(i).Tag.Get("json")
-				reflect.ValueOf(funcArgs).Elem().Field(i).Set(reflect.ValueOf(req.FormValue(jsonTag)))
-			}
-		}
-
-		ctx := req.Context()
-		outs := reflect.ValueOf(handlerFunc).Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(funcArgs)})
-		resultFunc := outs[0].Interface()
-		errFunc := outs[1].Interface()
-
-		resp.Header().Set("Content-Type", "application/json")
-		if errFunc != nil {
-			err := errFunc.(xerror.XError)
-			resp.WriteHeader(err.HttpStatus())
-			json.NewEncoder(resp).Encode(map[string]string{
-				"error": err.Message,
-			})
+	if handler.Permission != "" {
+		role, _ := roleFromCtx(req.Context())
+		if !hasPermission(role, handler.Permission) {
+			writeError(resp, xerror.ErrorMf(xerror.PermissionDenied, nil, "the %s permission is required", handler.Permission))
 			return
-		} else {
-			// json.NewEncoder(resp).Encode(map[string]interface{}{
-			// 	"data": resultFunc,
-			// })
-
-			json.NewEncoder(resp).Encode(resultFunc)
 		}
-
-	case HTML:
-		reflect.ValueOf(handlerFunc).Call([]reflect.Value{reflect.ValueOf(resp), reflect.ValueOf(req)})
 	}
+
+	handler.serve(resp, req)
 }
 
-func (s *RemiService) validToken(req *http.Request) (*http.Request, bool) {
-	token := req.Header.Get("Authorization")
+// validToken identifies the user of the access token sent in the Authorization header,
+// or in the token query param when tokenInQuery is set
+func (s *RemiService) validToken(req *http.Request, tokenInQuery bool) (*http.Request, bool) {
+	raw := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if raw == "" && tokenInQuery {
+		raw = req.URL.Query().Get("token")
+	}
+	if raw == "" {
+		return req, false
+	}
 
 	claims := make(jwt.MapClaims)
-	t, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+	t, err := jwt.ParseWithClaims(raw, claims, func(*jwt.Token) (interface{}, error) {
 		return []byte(s.jwtKey), nil
 	})
 	if err != nil {
@@ -241,14 +269,138 @@ func (s *RemiService) validToken(req *http.Request) (*http.Request, bool) {
 		return req, false
 	}
 
-	req = req.WithContext(context.WithValue(req.Context(), userAuthKey(0), id))
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return req, false
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return req, false
+	}
+
+	token := &accessToken{
+		JTI:       jti,
+		ExpiresAt: time.Unix(int64(exp), 0),
+	}
+	// tokens issued before iat was added to the claims don't carry it
+	if iat, ok := claims["iat"].(float64); ok {
+		issuedAt := time.UnixMilli(int64(math.Round(iat * 1000)))
+		token.IssuedAt = &issuedAt
+	}
+
+	user, ok := s.checkSession(req.Context(), id, token)
+	if !ok {
+		return req, false
+	}
+
+	ctx := context.WithValue(req.Context(), userAuthKey(0), id)
+	ctx = context.WithValue(ctx, userAuthKey(1), token)
+	// the role is read from the database rather than the claims so that role changes apply at once
+	ctx = context.WithValue(ctx, userAuthKey(2), user.Role)
+	req = req.WithContext(ctx)
 	return req, true
 }
 
+// checkSession reports whether the access token of the user is still accepted: it isn't revoked,
+// the user isn't banned and it was issued after the tokens of the user were last invalidated
+func (s *RemiService) checkSession(ctx context.Context, userID string, token *accessToken) (*entities.User, bool) {
+	revoked, err := s.revokedTokenRepo.IsRevoked(ctx, token.JTI)
+	if err != nil {
+		log.Println(err)
+		return nil, false
+	}
+	if revoked {
+		return nil, false
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		log.Println(err)
+		return nil, false
+	}
+	if user.DisabledAt != nil {
+		return nil, false
+	}
+	if user.TokensValidAfter != nil && (token.IssuedAt == nil || token.IssuedAt.Before(user.TokensValidAfter.Truncate(time.Millisecond))) {
+		return nil, false
+	}
+
+	return user, true
+}
+
+// streamNotifications serves the notifications of the caller until the access token expires or stops being
+// accepted, e.g. after a logout, since the stream would outlive it otherwise. Pages reconnect with their current token.
+func (s *RemiService) streamNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromCtx(r.Context())
+	token, _ := accessTokenFromCtx(r.Context())
+
+	ctx, cancel := context.WithDeadline(r.Context(), token.ExpiresAt)
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(notificationSessionCheck)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, ok := s.checkSession(ctx, userID, token); !ok {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	s.hub.Stream(w, r.WithContext(ctx))
+}
+
+// clientIP returns the IP of the client, read from the header set by the reverse proxy when it's configured
+func (s *RemiService) clientIP(req *http.Request) string {
+	if s.clientIPHeader != "" {
+		if ip := strings.TrimSpace(req.Header.Get(s.clientIPHeader)); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+type clientIPKey struct{}
+
+func clientIPFromCtx(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPKey{}).(string)
+	return ip, ok
+}
+
 type userAuthKey int8
 
+// accessToken identifies the JWT of the request so it can be revoked, IssuedAt is nil for old tokens
+type accessToken struct {
+	JTI       string
+	IssuedAt  *time.Time
+	ExpiresAt time.Time
+}
+
 func userIDFromCtx(ctx context.Context) (string, bool) {
 	v := ctx.Value(userAuthKey(0))
 	id, ok := v.(string)
 	return id, ok
 }
+
+func accessTokenFromCtx(ctx context.Context) (*accessToken, bool) {
+	v := ctx.Value(userAuthKey(1))
+	token, ok := v.(*accessToken)
+	return token, ok
+}
+
+func roleFromCtx(ctx context.Context) (string, bool) {
+	v := ctx.Value(userAuthKey(2))
+	role, ok := v.(string)
+	return role, ok
+}
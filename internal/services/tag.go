@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"remi/internal/repositories"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+const (
+	defaultTagsLimit = 50
+	maxTagLength     = 30
+)
+
+var _ up.TagService = &TagService{}
+
+type TagService struct {
+	tagRepo *repositories.TagRepository
+}
+
+func NewTagService(db *sql.DB) *TagService {
+	return &TagService{
+		tagRepo: repositories.NewTagRepository(db),
+	}
+}
+
+// ListTags lists the most used tags with their number of movies, e.g. to draw a tag cloud
+func (s *TagService) ListTags(ctx context.Context, req *up.ListTagsRequest) (*up.ListTagsResponse, error) {
+	limit := defaultTagsLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+
+	counts, err := s.tagRepo.Count(ctx, limit)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.tagRepo.Count: %w", err))
+	}
+
+	resp := &up.ListTagsResponse{}
+	for _, count := range counts {
+		resp.Tags = append(resp.Tags, &up.TagCount{
+			Name:  count.Name,
+			Count: count.Count,
+		})
+	}
+
+	return resp, nil
+}
+
+// normalizeTags lowercases the tags, strips a leading # and joins their words with '-', so that
+// "#Go Talks" and "go_talks" are the same tag. Duplicates are dropped, field names the request field
+// in the error returned for invalid tags.
+func normalizeTags(field string, tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		words := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+			return unicode.IsSpace(r) || r == '_' || r == '-'
+		})
+		tag = strings.Join(words, "-")
+
+		description := ""
+		switch {
+		case tag == "":
+			description = "can't be blank"
+		case utf8.RuneCountInString(tag) > maxTagLength:
+			description = fmt.Sprintf("can't be longer than %d characters", maxTagLength)
+		case strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' }) != -1:
+			description = "can only contain letters, digits and '-'"
+		}
+		if description != "" {
+			return nil, xerror.ErrorMf(xerror.InvalidArgument, nil, "%s %s", field, description).
+				WithDetails(xerror.FieldViolation{Field: field, Description: description})
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized, nil
+}
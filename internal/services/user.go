@@ -3,14 +3,20 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"html/template"
+	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"remi/internal/entities"
 	"remi/internal/repositories"
+	"remi/pkg/blobstore"
+	"remi/pkg/config"
 	"remi/pkg/crypto"
+	"remi/pkg/golibs/database"
 	"remi/pkg/golibs/idutil"
 	"remi/pkg/xerror"
 	"remi/up"
@@ -20,37 +26,88 @@ import (
 
 var _ up.UserService = &UserService{}
 
+const refreshTokenBytes = 32
+
 type UserService struct {
-	userRepo *repositories.UserRepository
-	jwtKey   string
-	url      string
+	db               *sql.DB
+	userRepo         *repositories.UserRepository
+	refreshTokenRepo *repositories.RefreshTokenRepository
+	revokedTokenRepo *repositories.RevokedTokenRepository
+	loginLimiter     *loginLimiter
+	passwordPolicy   *crypto.PasswordPolicy
+	passwordHasher   crypto.PasswordHasher
+	blobs            blobstore.Store
+	jwtKey           string
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
+	url              string
 }
 
-func NewUserService(db *sql.DB, jwtKey, url string) *UserService {
+// NewUserService panics when the breached passwords file of the policy can't be read,
+// blobs stores the avatars
+func NewUserService(db *sql.DB, cfg *config.Config, blobs blobstore.Store) *UserService {
 	return &UserService{
-		userRepo: repositories.NewUserRepository(db),
-		jwtKey:   jwtKey,
-		url:      url,
+		db:               db,
+		userRepo:         repositories.NewUserRepository(db),
+		refreshTokenRepo: repositories.NewRefreshTokenRepository(db),
+		revokedTokenRepo: repositories.NewRevokedTokenRepository(db),
+		loginLimiter:     newLoginLimiter(db, cfg.LoginLockout),
+		passwordPolicy:   newPasswordPolicy(cfg.PasswordPolicy),
+		passwordHasher:   newPasswordHasher(cfg.PasswordHashing),
+		blobs:            blobs,
+		jwtKey:           cfg.JWTSecret,
+		accessTokenTTL:   cfg.AccessTokenTTL,
+		refreshTokenTTL:  cfg.RefreshTokenTTL,
+		url:              cfg.URL,
 	}
 }
 
-func (s *UserService) Register(ctx context.Context, req *up.RegisterRequest) (*up.RegisterResponse, error) {
-	if err := req.Validate(); err != nil {
-		return nil, xerror.Error(xerror.InvalidArgument, err)
+func newPasswordHasher(cfg config.PasswordHashing) crypto.PasswordHasher {
+	if cfg.Algorithm == crypto.AlgorithmBcrypt {
+		return crypto.NewBcryptHasher(cfg.BcryptCost)
+	}
+	return crypto.NewArgon2idHasher(cfg.Argon2Time, cfg.Argon2Memory, cfg.Argon2Threads)
+}
+
+func newPasswordPolicy(cfg config.PasswordPolicy) *crypto.PasswordPolicy {
+	policy := &crypto.PasswordPolicy{
+		MinLength:      cfg.MinLength,
+		MinCharClasses: cfg.MinCharClasses,
+	}
+	if cfg.BreachedPasswordsFile == "" {
+		return policy
 	}
 
+	f, err := os.Open(cfg.BreachedPasswordsFile)
+	if err != nil {
+		log.Panicf("os.Open: %v", err)
+	}
+	defer f.Close()
+
+	if err := policy.LoadBreached(f); err != nil {
+		log.Panicf("policy.LoadBreached: %v", err)
+	}
+
+	return policy
+}
+
+func (s *UserService) Register(ctx context.Context, req *up.RegisterRequest) (*up.RegisterResponse, error) {
 	user, err := s.userRepo.FindByUsername(ctx, req.Username)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, xerror.Error(xerror.Internal, err)
 	}
 
 	if user != nil {
-		return nil, xerror.Error(xerror.InvalidArgument, fmt.Errorf("user exists with the given username"))
+		return nil, xerror.ErrorM(xerror.AlreadyExists, nil, "user exists with the given username")
+	}
+
+	if err := s.checkPasswordPolicy("password", req.Password); err != nil {
+		return nil, err
 	}
 
-	password, err := crypto.HashPassword(req.Password)
+	password, err := s.passwordHasher.Hash(req.Password)
 	if err != nil {
-		return nil, xerror.Error(xerror.Internal, fmt.Errorf("crypto.HashPassword: %w", err))
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.passwordHasher.Hash: %w", err))
 	}
 
 	now := time.Now()
@@ -59,51 +116,228 @@ func (s *UserService) Register(ctx context.Context, req *up.RegisterRequest) (*u
 		Username:  req.Username,
 		Password:  password,
 		Name:      req.Name,
+		Role:      entities.RoleUser,
 		CreatedAt: &now,
 		UpdatedAt: &now,
 	})
 	if err != nil {
+		// the username was taken by a concurrent registration
+		if database.IsUniqueViolation(err) {
+			return nil, xerror.ErrorM(xerror.AlreadyExists, err, "user exists with the given username")
+		}
 		return nil, xerror.Error(xerror.Internal, err)
 	}
 
 	return &up.RegisterResponse{}, nil
 }
 
+// Login rejects the username and the IP of the client with RateLimited errors while they are locked out,
+// failed logins of unknown usernames are counted too
 func (s *UserService) Login(ctx context.Context, req *up.LoginRequest) (*up.LoginResponse, error) {
-	if err := req.Validate(); err != nil {
+	ip, _ := clientIPFromCtx(ctx)
+	if err := s.loginLimiter.check(ctx, req.Username, ip); err != nil {
 		return nil, err
 	}
 
 	user, err := s.userRepo.FindByUsername(ctx, req.Username)
-	if err != nil {
-		if err != sql.ErrNoRows {
-			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByUsername: %w", err))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByUsername: %w", err))
+	}
+
+	ok := false
+	if user != nil {
+		ok, err = s.passwordHasher.Verify(req.Password, user.Password)
+		if err != nil {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.passwordHasher.Verify: %w", err))
 		}
-		return nil, xerror.Error(xerror.UnAuthorized, fmt.Errorf("incorrect username/pwd"))
+	}
+	if !ok {
+		if err := s.loginLimiter.fail(ctx, req.Username, ip); err != nil {
+			return nil, err
+		}
+		return nil, xerror.ErrorM(xerror.UnAuthorized, nil, "incorrect username/pwd")
+	}
+	if err := s.loginLimiter.succeed(ctx, req.Username); err != nil {
+		return nil, err
+	}
+	if s.passwordHasher.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user, req.Password)
+	}
+	if user.DisabledAt != nil {
+		return nil, xerror.ErrorM(xerror.PermissionDenied, nil, "account is disabled")
 	}
 
-	if user == nil || !crypto.CheckPasswordHash(req.Password, user.Password) {
-		return nil, xerror.Error(xerror.UnAuthorized, fmt.Errorf("incorrect username/pwd"))
+	token, err := s.createToken(user.ID, user.Username, user.Role)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, err)
 	}
 
-	token, err := s.createToken(user.ID, user.Username)
+	refreshToken, err := s.createRefreshToken(ctx, s.refreshTokenRepo, user.ID, idutil.NewID(), idutil.NewID())
 	if err != nil {
 		return nil, xerror.Error(xerror.Internal, err)
 	}
 
 	return &up.LoginResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Name:     user.Name,
-		Token:    token,
+		ID:           user.ID,
+		Username:     user.Username,
+		Name:         user.Name,
+		Role:         user.Role,
+		Token:        token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
-func (s *UserService) createToken(id, username string) (string, error) {
+// checkPasswordPolicy returns an InvalidArgument error for the field when the password is too weak
+func (s *UserService) checkPasswordPolicy(field, password string) error {
+	if err := s.passwordPolicy.Check(password); err != nil {
+		return xerror.ErrorM(xerror.InvalidArgument, nil, field+" "+err.Error()).
+			WithDetails(xerror.FieldViolation{Field: field, Description: err.Error()})
+	}
+
+	return nil
+}
+
+// rehashPassword upgrades the stored hash to the current algorithm and parameters,
+// failures are only logged since the login itself succeeded
+func (s *UserService) rehashPassword(ctx context.Context, user *entities.User, password string) {
+	hash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		log.Printf("s.passwordHasher.Hash: %v", err)
+		return
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, user.Password, hash, time.Now()); err != nil {
+		log.Printf("s.userRepo.UpdatePassword: %v", err)
+		return
+	}
+	user.Password = hash
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens. Presenting a refresh token which was
+// already exchanged means it leaked, so every token issued from the same login is revoked.
+func (s *UserService) Refresh(ctx context.Context, req *up.RefreshRequest) (*up.RefreshResponse, error) {
+	oldToken, err := s.refreshTokenRepo.FindByHash(ctx, crypto.HashToken(req.RefreshToken))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.refreshTokenRepo.FindByHash: %w", err))
+		}
+		return nil, xerror.ErrorM(xerror.UnAuthorized, nil, "invalid refresh token")
+	}
+
+	now := time.Now()
+	if oldToken.RevokedAt != nil {
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, oldToken.FamilyID, now); err != nil {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.refreshTokenRepo.RevokeFamily: %w", err))
+		}
+		return nil, xerror.ErrorM(xerror.UnAuthorized, nil, "invalid refresh token")
+	}
+	if oldToken.ExpiresAt.Before(now) {
+		return nil, xerror.ErrorM(xerror.UnAuthorized, nil, "refresh token expired")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, oldToken.UserID)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.userRepo.FindByID: %w", err))
+	}
+	if user.DisabledAt != nil {
+		return nil, xerror.ErrorM(xerror.PermissionDenied, nil, "account is disabled")
+	}
+
+	// the old token is revoked only if its successor is stored, so a failed insert doesn't log the user out
+	var refreshToken string
+	newTokenID := idutil.NewID()
+	err = database.ExecInTx(ctx, s.db, func(tx *sql.Tx) error {
+		refreshTokenRepo := s.refreshTokenRepo.WithTx(tx)
+		if err := refreshTokenRepo.Rotate(ctx, oldToken.ID, newTokenID, now); err != nil {
+			return fmt.Errorf("s.refreshTokenRepo.Rotate: %w", err)
+		}
+
+		var err error
+		refreshToken, err = s.createRefreshToken(ctx, refreshTokenRepo, user.ID, newTokenID, oldToken.FamilyID)
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// the token was exchanged by a concurrent request in the meantime
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, oldToken.FamilyID, now); err != nil {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.refreshTokenRepo.RevokeFamily: %w", err))
+		}
+		return nil, xerror.ErrorM(xerror.UnAuthorized, err, "invalid refresh token")
+	}
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, err)
+	}
+
+	token, err := s.createToken(user.ID, user.Username, user.Role)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, err)
+	}
+
+	return &up.RefreshResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *UserService) Logout(ctx context.Context, req *up.LogoutRequest) (*up.LogoutResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	token, _ := accessTokenFromCtx(ctx)
+	err := s.revokedTokenRepo.Create(ctx, &entities.RevokedToken{
+		JTI:       token.JTI,
+		ExpiresAt: &token.ExpiresAt,
+	})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.revokedTokenRepo.Create: %w", err))
+	}
+
+	if req.RefreshToken != "" {
+		refreshToken, err := s.refreshTokenRepo.FindByHash(ctx, crypto.HashToken(req.RefreshToken))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.refreshTokenRepo.FindByHash: %w", err))
+		}
+		if refreshToken != nil && refreshToken.UserID == userID {
+			if err := s.refreshTokenRepo.RevokeFamily(ctx, refreshToken.FamilyID, time.Now()); err != nil {
+				return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.refreshTokenRepo.RevokeFamily: %w", err))
+			}
+		}
+	}
+
+	return &up.LogoutResponse{}, nil
+}
+
+// createRefreshToken stores the hash of a new refresh token with refreshTokenRepo and returns the token
+func (s *UserService) createRefreshToken(ctx context.Context, refreshTokenRepo *repositories.RefreshTokenRepository, userID, id, familyID string) (string, error) {
+	token, err := crypto.RandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", fmt.Errorf("crypto.RandomToken: %w", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.refreshTokenTTL)
+	err = refreshTokenRepo.Create(ctx, &entities.RefreshToken{
+		ID:        id,
+		UserID:    userID,
+		TokenHash: crypto.HashToken(token),
+		FamilyID:  familyID,
+		ExpiresAt: &expiresAt,
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return "", fmt.Errorf("refreshTokenRepo.Create: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s *UserService) createToken(id, username, role string) (string, error) {
 	atClaims := jwt.MapClaims{}
+	atClaims["jti"] = idutil.NewID()
 	atClaims["id"] = id
 	atClaims["username"] = username
-	atClaims["exp"] = time.Now().Add(time.Hour * 2).Unix()
+	atClaims["role"] = role
+	now := time.Now()
+	// iat keeps the milliseconds so that tokens issued right after an invalidation are accepted
+	atClaims["iat"] = float64(now.UnixMilli()) / 1000
+	atClaims["exp"] = now.Add(s.accessTokenTTL).Unix()
 	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
 	token, err := at.SignedString([]byte(s.jwtKey))
 	if err != nil {
@@ -145,7 +379,7 @@ func (s *UserService) GetRegisterPage(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *UserService) GetHomePage(w http.ResponseWriter, r *http.Request) {
-	tmpl := template.Must(template.ParseFiles("templates/home.html"))
+	tmpl := template.Must(template.ParseFiles("templates/home.html", "templates/session.html"))
 
 	data := Data{
 		URL: s.url,
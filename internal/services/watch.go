@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"remi/internal/entities"
+	"remi/internal/repositories"
+	"remi/pkg/golibs/cursor"
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+const defaultWatchItemsLimit = 20
+
+var _ up.WatchService = &WatchService{}
+
+type WatchService struct {
+	watchLaterRepo *repositories.WatchLaterRepository
+	historyRepo    *repositories.WatchHistoryRepository
+	movieRepo      *repositories.MovieRepository
+	movies         *MovieService
+}
+
+func NewWatchService(db *sql.DB, movies *MovieService) *WatchService {
+	return &WatchService{
+		watchLaterRepo: repositories.NewWatchLaterRepository(db),
+		historyRepo:    repositories.NewWatchHistoryRepository(db),
+		movieRepo:      repositories.NewMovieRepository(db),
+		movies:         movies,
+	}
+}
+
+// AddWatchLater saves the movie to watch later, saving it again is a no-op
+func (s *WatchService) AddWatchLater(ctx context.Context, req *up.AddWatchLaterRequest) (*up.AddWatchLaterResponse, error) {
+	if err := s.checkMovieExists(ctx, req.MovieID); err != nil {
+		return nil, err
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	now := time.Now()
+	err := s.watchLaterRepo.Add(ctx, &entities.WatchLaterItem{
+		UserID:  userID,
+		MovieID: req.MovieID,
+		AddedAt: &now,
+	})
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.watchLaterRepo.Add: %w", err))
+	}
+
+	return &up.AddWatchLaterResponse{}, nil
+}
+
+func (s *WatchService) RemoveWatchLater(ctx context.Context, req *up.RemoveWatchLaterRequest) (*up.RemoveWatchLaterResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	if err := s.watchLaterRepo.Remove(ctx, userID, req.MovieID); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.watchLaterRepo.Remove: %w", err))
+	}
+
+	return &up.RemoveWatchLaterResponse{}, nil
+}
+
+func (s *WatchService) ListWatchLater(ctx context.Context, req *up.ListWatchLaterRequest) (*up.ListWatchLaterResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	args, limit, err := watchItemsArgs(userID, req.Cursor, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.watchLaterRepo.List(ctx, args)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.watchLaterRepo.List: %w", err))
+	}
+
+	resp := &up.ListWatchLaterResponse{}
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[limit-1]
+		resp.NextCursor = cursor.Encode(&cursor.Cursor{CreatedAt: *last.AddedAt, ID: last.MovieID})
+	}
+
+	movieIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		movieIDs = append(movieIDs, item.MovieID)
+	}
+	movies, err := s.toMovies(ctx, movieIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if movie, ok := movies[item.MovieID]; ok {
+			resp.Items = append(resp.Items, &up.WatchLaterItem{
+				Movie:   movie,
+				AddedAt: *item.AddedAt,
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+// RecordWatch records that the caller viewed the movie, viewing it again moves it to the top of the history
+func (s *WatchService) RecordWatch(ctx context.Context, req *up.RecordWatchRequest) (*up.RecordWatchResponse, error) {
+	if err := s.checkMovieExists(ctx, req.MovieID); err != nil {
+		return nil, err
+	}
+
+	userID, _ := userIDFromCtx(ctx)
+	if err := s.movies.recordWatch(ctx, userID, req.MovieID); err != nil {
+		return nil, err
+	}
+
+	return &up.RecordWatchResponse{}, nil
+}
+
+func (s *WatchService) RemoveWatchHistory(ctx context.Context, req *up.RemoveWatchHistoryRequest) (*up.RemoveWatchHistoryResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	if err := s.historyRepo.Remove(ctx, userID, req.MovieID); err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.historyRepo.Remove: %w", err))
+	}
+
+	return &up.RemoveWatchHistoryResponse{}, nil
+}
+
+func (s *WatchService) ListWatchHistory(ctx context.Context, req *up.ListWatchHistoryRequest) (*up.ListWatchHistoryResponse, error) {
+	userID, _ := userIDFromCtx(ctx)
+	args, limit, err := watchItemsArgs(userID, req.Cursor, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.historyRepo.List(ctx, args)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.historyRepo.List: %w", err))
+	}
+
+	resp := &up.ListWatchHistoryResponse{}
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[limit-1]
+		resp.NextCursor = cursor.Encode(&cursor.Cursor{CreatedAt: *last.WatchedAt, ID: last.MovieID})
+	}
+
+	movieIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		movieIDs = append(movieIDs, item.MovieID)
+	}
+	movies, err := s.toMovies(ctx, movieIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if movie, ok := movies[item.MovieID]; ok {
+			resp.Items = append(resp.Items, &up.WatchHistoryItem{
+				Movie:     movie,
+				WatchedAt: *item.WatchedAt,
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+// watchItemsArgs fetches one more item than the page size to know if there is a next page
+func watchItemsArgs(userID, pageCursor string, limit *int) (*repositories.ListWatchItemsArgs, int, error) {
+	pageLimit := defaultWatchItemsLimit
+	if limit != nil {
+		pageLimit = *limit
+	}
+
+	args := &repositories.ListWatchItemsArgs{
+		UserID: userID,
+		Limit:  pageLimit + 1,
+	}
+	if pageCursor != "" {
+		after, err := cursor.Decode(pageCursor)
+		if err != nil {
+			return nil, 0, xerror.ErrorM(xerror.InvalidArgument, err, "invalid cursor")
+		}
+		args.After = after
+	}
+
+	return args, pageLimit, nil
+}
+
+// toMovies converts the visible movies among movieIDs keyed by id
+func (s *WatchService) toMovies(ctx context.Context, movieIDs []string, userID string) (map[string]*up.Movie, error) {
+	result := make(map[string]*up.Movie)
+	if len(movieIDs) == 0 {
+		return result, nil
+	}
+
+	movies, err := s.movieRepo.ListByIDs(ctx, movieIDs)
+	if err != nil {
+		return nil, xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.ListByIDs: %w", err))
+	}
+
+	converted, err := s.movies.toMovies(ctx, movies, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, movie := range converted {
+		result[movie.ID] = movie
+	}
+
+	return result, nil
+}
+
+func (s *WatchService) checkMovieExists(ctx context.Context, movieID string) error {
+	if _, err := s.movieRepo.FindByID(ctx, movieID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return xerror.Error(xerror.Internal, fmt.Errorf("s.movieRepo.FindByID: %w", err))
+		}
+		return xerror.ErrorMf(xerror.NotFound, nil, "movie (%s) not found", movieID)
+	}
+
+	return nil
+}
@@ -23,14 +23,14 @@ func main() {
 	}
 
 	if err := goose.SetDialect("postgres"); err != nil {
-		log.Panicf("goose.SetDialect: %w", err)
+		log.Panicf("goose.SetDialect: %v", err)
 	}
 
 	if err := goose.Up(db, "migrations/sql"); err != nil {
-		log.Panicf("goose.Up: %w", err)
+		log.Panicf("goose.Up: %v", err)
 	}
 
-	remiService := services.NewRemiService(db, cfg.JWTSecret, cfg.URL)
+	remiService := services.NewRemiService(db, cfg)
 
 	log.Printf("HTTP server listening at %v", cfg.HTTP.Address())
 
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE "movies" ADD COLUMN provider TEXT NOT NULL DEFAULT 'youtube';
+
+-- +goose Down
+ALTER TABLE "movies" DROP COLUMN provider;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE "movies"
+   ADD COLUMN author TEXT NOT NULL DEFAULT '',
+   ADD COLUMN duration INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE "movies"
+   DROP COLUMN author,
+   DROP COLUMN duration;
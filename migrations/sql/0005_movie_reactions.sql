@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE "movie_reactions" (
+   movie_id TEXT NOT NULL REFERENCES movies(id),
+   user_id TEXT NOT NULL REFERENCES users(id),
+   type TEXT NOT NULL CHECK (type IN ('like', 'dislike')),
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   PRIMARY KEY (movie_id, user_id)
+);
+
+CREATE INDEX movie_reactions_user_id_idx ON "movie_reactions"(user_id);
+
+-- +goose Down
+DROP TABLE "movie_reactions";
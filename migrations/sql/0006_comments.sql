@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE "comments" (
+   id TEXT PRIMARY KEY,
+   movie_id TEXT NOT NULL REFERENCES movies(id),
+   user_id TEXT NOT NULL REFERENCES users(id),
+   parent_id TEXT REFERENCES comments(id),
+   content TEXT NOT NULL,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX comments_movie_id_created_at_idx ON "comments"(movie_id, created_at DESC, id DESC) WHERE parent_id IS NULL;
+CREATE INDEX comments_parent_id_idx ON "comments"(parent_id);
+
+-- +goose Down
+DROP TABLE "comments";
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE "movies" ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+   setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+   setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX movies_search_vector_idx ON "movies" USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX movies_search_vector_idx;
+ALTER TABLE "movies" DROP COLUMN search_vector;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE "refresh_tokens" (
+   id TEXT PRIMARY KEY,
+   user_id TEXT NOT NULL REFERENCES users(id),
+   token_hash TEXT NOT NULL UNIQUE,
+   family_id TEXT NOT NULL,
+   expires_at TIMESTAMPTZ NOT NULL,
+   revoked_at TIMESTAMPTZ,
+   replaced_by TEXT,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON "refresh_tokens"(family_id);
+
+CREATE TABLE "revoked_tokens" (
+   jti TEXT PRIMARY KEY,
+   expires_at TIMESTAMPTZ NOT NULL,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE "revoked_tokens";
+DROP TABLE "refresh_tokens";
@@ -0,0 +1,30 @@
+-- +goose Up
+ALTER TABLE "users"
+   ADD COLUMN role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'moderator', 'admin')),
+   ADD COLUMN disabled_at TIMESTAMPTZ;
+
+ALTER TABLE "movies"
+   ADD COLUMN hidden_at TIMESTAMPTZ,
+   ADD COLUMN hidden_by TEXT REFERENCES users(id);
+
+CREATE TABLE "movie_reports" (
+   id TEXT PRIMARY KEY,
+   movie_id TEXT NOT NULL REFERENCES movies(id),
+   user_id TEXT NOT NULL REFERENCES users(id),
+   reason TEXT NOT NULL,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX movie_reports_unresolved_idx ON "movie_reports"(created_at DESC) WHERE resolved_at IS NULL;
+
+-- +goose Down
+DROP TABLE "movie_reports";
+
+ALTER TABLE "movies"
+   DROP COLUMN hidden_at,
+   DROP COLUMN hidden_by;
+
+ALTER TABLE "users"
+   DROP COLUMN role,
+   DROP COLUMN disabled_at;
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE "login_attempts" (
+   scope TEXT NOT NULL CHECK (scope IN ('username', 'ip')),
+   subject TEXT NOT NULL,
+   failures INT NOT NULL,
+   last_failed_at TIMESTAMPTZ NOT NULL,
+   locked_until TIMESTAMPTZ,
+   PRIMARY KEY (scope, subject)
+);
+
+CREATE TABLE "login_lockouts" (
+   id TEXT PRIMARY KEY,
+   scope TEXT NOT NULL,
+   subject TEXT NOT NULL,
+   ip TEXT NOT NULL,
+   failures INT NOT NULL,
+   locked_until TIMESTAMPTZ NOT NULL,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX login_lockouts_created_at_idx ON "login_lockouts"(created_at DESC);
+
+-- +goose Down
+DROP TABLE "login_lockouts";
+DROP TABLE "login_attempts";
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE "playlists" (
+   id TEXT PRIMARY KEY,
+   user_id TEXT NOT NULL REFERENCES users(id),
+   name TEXT NOT NULL,
+   description TEXT NOT NULL DEFAULT '',
+   visibility TEXT NOT NULL DEFAULT 'private' CHECK (visibility IN ('public', 'private')),
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX playlists_created_at_idx ON "playlists"(created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX playlists_user_id_idx ON "playlists"(user_id);
+
+CREATE TABLE "playlist_items" (
+   playlist_id TEXT NOT NULL REFERENCES playlists(id),
+   movie_id TEXT NOT NULL REFERENCES movies(id),
+   position INT NOT NULL,
+   added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   PRIMARY KEY (playlist_id, movie_id)
+);
+
+CREATE INDEX playlist_items_playlist_id_position_idx ON "playlist_items"(playlist_id, position);
+
+-- +goose Down
+DROP TABLE "playlist_items";
+DROP TABLE "playlists";
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE "tags" (
+   id TEXT PRIMARY KEY,
+   name TEXT NOT NULL UNIQUE,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE "movie_tags" (
+   movie_id TEXT NOT NULL REFERENCES movies(id),
+   tag_id TEXT NOT NULL REFERENCES tags(id),
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   PRIMARY KEY (movie_id, tag_id)
+);
+
+CREATE INDEX movie_tags_tag_id_idx ON "movie_tags"(tag_id);
+
+-- +goose Down
+DROP TABLE "movie_tags";
+DROP TABLE "tags";
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE "follows" (
+   follower_id TEXT NOT NULL REFERENCES users(id),
+   followee_id TEXT NOT NULL REFERENCES users(id),
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   PRIMARY KEY (follower_id, followee_id),
+   CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX follows_followee_id_idx ON "follows"(followee_id, created_at DESC);
+CREATE INDEX movies_shared_by_created_at_idx ON "movies"(shared_by, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX movies_shared_by_created_at_idx;
+DROP TABLE "follows";
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE "watch_later" (
+   user_id TEXT NOT NULL REFERENCES users(id),
+   movie_id TEXT NOT NULL REFERENCES movies(id),
+   added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX watch_later_user_id_added_at_idx ON "watch_later"(user_id, added_at DESC, movie_id DESC);
+
+CREATE TABLE "watch_history" (
+   user_id TEXT NOT NULL REFERENCES users(id),
+   movie_id TEXT NOT NULL REFERENCES movies(id),
+   watched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX watch_history_user_id_watched_at_idx ON "watch_history"(user_id, watched_at DESC, movie_id DESC);
+
+-- +goose Down
+DROP TABLE "watch_history";
+DROP TABLE "watch_later";
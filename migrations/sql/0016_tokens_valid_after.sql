@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE "users"
+   ADD COLUMN tokens_valid_after TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE "users"
+   DROP COLUMN tokens_valid_after;
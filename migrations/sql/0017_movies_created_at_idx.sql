@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX movies_created_at_idx ON "movies"(created_at DESC, id DESC) WHERE deleted_at IS NULL AND hidden_at IS NULL;
+
+-- +goose Down
+DROP INDEX movies_created_at_idx;
@@ -0,0 +1,121 @@
+// Package blobstore stores binary objects, e.g. uploaded images, by key
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store is implemented by the storage backends. Keys are chosen by the caller, they are
+// flat names made of letters, digits, '.', '-' and '_' which don't start with a '.'.
+type Store interface {
+	// Put creates or replaces the blob
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns ErrNotFound when there is no blob with the key
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete is a no-op when there is no blob with the key
+	Delete(ctx context.Context, key string) error
+}
+
+var _ Store = &FileStore{}
+
+// FileStore stores blobs as files of a local directory, which is created on the first Put
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Put writes to a temporary file first so that readers never see a partial blob
+func (s *FileStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	f, err := os.CreateTemp(s.dir, ".tmp-"+key+"-*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("io.Copy: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("f.Close: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("os.Open: %w", err)
+	}
+
+	return f, nil
+}
+
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("os.Remove: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+// ValidKey reports whether the key can be used with every Store
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, ".") {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "blobs")
+	store := NewFileStore(dir)
+
+	_, err := store.Open(ctx, "avatar.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Put(ctx, "avatar.png", strings.NewReader("first")))
+	require.NoError(t, store.Put(ctx, "avatar.png", strings.NewReader("second")))
+
+	r, err := store.Open(ctx, "avatar.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "second", string(data))
+
+	// temporary files are cleaned up
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, store.Delete(ctx, "avatar.png"))
+	require.NoError(t, store.Delete(ctx, "avatar.png"))
+	_, err = store.Open(ctx, "avatar.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFileStore_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir())
+
+	for _, key := range []string{"", "../secret", "a/b", ".hidden", `a\b`} {
+		assert.ErrorIs(t, store.Put(ctx, key, strings.NewReader("data")), ErrInvalidKey, key)
+		_, err := store.Open(ctx, key)
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+		assert.ErrorIs(t, store.Delete(ctx, key), ErrInvalidKey, key)
+	}
+}
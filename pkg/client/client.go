@@ -0,0 +1,246 @@
+// Package client is a Go client of the Remi API built on the request and response types of the up package
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"remi/up"
+)
+
+const (
+	defaultMaxRetries   = 2
+	defaultRetryBackoff = 200 * time.Millisecond
+)
+
+// HTTPClient sends http requests, it's satisfied by *http.Client
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client calls the Remi API. The tokens returned by Login and Refresh are kept and sent with the
+// following calls, and an expired access token is refreshed once before the call fails.
+type Client struct {
+	baseURL      string
+	httpClient   HTTPClient
+	maxRetries   int
+	retryBackoff time.Duration
+
+	mu           sync.RWMutex
+	token        string
+	refreshToken string
+	// refreshMu lets a single call refresh the tokens at a time, since the server revokes
+	// the session when a refresh token which was already exchanged is presented again
+	refreshMu sync.Mutex
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTokens restores a session, e.g. tokens which were saved after a previous login
+func WithTokens(token, refreshToken string) Option {
+	return func(c *Client) {
+		c.token = token
+		c.refreshToken = refreshToken
+	}
+}
+
+// WithRetries sets how many times idempotent calls are retried after a network error or an unavailable server,
+// the backoff doubles after each attempt
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		maxRetries:   defaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Tokens returns the current access and refresh tokens
+func (c *Client) Tokens() (token, refreshToken string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token, c.refreshToken
+}
+
+func (c *Client) setTokens(token, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.refreshToken = refreshToken
+}
+
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	// idempotent calls are retried, every GET is
+	idempotent bool
+	// auth calls send the access token and refresh it once when it's rejected
+	auth bool
+}
+
+func (c *Client) do(ctx context.Context, cl call, resp interface{}) error {
+	sentToken, _ := c.Tokens()
+	err := c.doWithRetries(ctx, cl, resp)
+
+	var apiErr *Error
+	if cl.auth && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		refreshed, err := c.refreshStaleToken(ctx, sentToken)
+		if err != nil {
+			return err
+		}
+		if refreshed {
+			return c.doWithRetries(ctx, cl, resp)
+		}
+	}
+
+	return err
+}
+
+// refreshStaleToken refreshes the tokens after staleToken was rejected and reports whether the call can be
+// retried. Concurrent calls wait for the first one to refresh and then use its tokens instead of refreshing again.
+func (c *Client) refreshStaleToken(ctx context.Context, staleToken string) (bool, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	token, refreshToken := c.Tokens()
+	if token != staleToken {
+		return true, nil
+	}
+	if refreshToken == "" {
+		return false, nil
+	}
+	if _, err := c.Refresh(ctx, &up.RefreshRequest{RefreshToken: refreshToken}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *Client) doWithRetries(ctx context.Context, cl call, resp interface{}) error {
+	retries := 0
+	if cl.idempotent || cl.method == http.MethodGet {
+		retries = c.maxRetries
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, cl, resp)
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, cl call, resp interface{}) error {
+	var body io.Reader
+	if cl.body != nil {
+		data, err := json.Marshal(cl.body)
+		if err != nil {
+			return fmt.Errorf("json.Marshal: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, cl.method, c.baseURL+cl.path, body)
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	if cl.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(cl.query) > 0 {
+		query := req.URL.Query()
+		for k, v := range cl.query {
+			query[k] = v
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+	if token, _ := c.Tokens(); cl.auth && token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &networkError{err: err}
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return decodeError(httpResp)
+	}
+
+	if resp == nil {
+		return nil
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(resp); err != nil {
+		return fmt.Errorf("json.Decode: %w", err)
+	}
+
+	return nil
+}
+
+// networkError is returned when the server couldn't be reached
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string {
+	return e.err.Error()
+}
+
+func (e *networkError) Unwrap() error {
+	return e.err
+}
+
+func retryable(err error) bool {
+	var netErr *networkError
+	if errors.As(err, &netErr) {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+
+	return false
+}
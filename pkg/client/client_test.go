@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"remi/pkg/xerror"
+	"remi/up"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Login(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/login":
+			json.NewEncoder(w).Encode(up.LoginResponse{Token: "token", RefreshToken: "refresh-token"})
+		case "/api/v2/movies":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, "5", r.URL.Query().Get("limit"))
+			assert.Equal(t, []string{"golang", "talks"}, r.URL.Query()["tags"])
+			json.NewEncoder(w).Encode(up.ListMoviesResponse{Movies: []*up.Movie{{ID: "movie-id"}}})
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, &up.LoginRequest{Username: "username", Password: "password"})
+	require.NoError(t, err)
+
+	token, refreshToken := c.Tokens()
+	assert.Equal(t, "token", token)
+	assert.Equal(t, "refresh-token", refreshToken)
+
+	limit := 5
+	resp, err := c.ListMovies(ctx, &up.ListMoviesRequest{Limit: &limit, Tags: []string{"golang", "talks"}})
+	require.NoError(t, err)
+	assert.Equal(t, "movie-id", resp.Movies[0].ID)
+}
+
+func TestClient_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(up.ErrorResponse{Error: "user exists with the given username", Code: "already_exists"})
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).Register(context.Background(), &up.RegisterRequest{})
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, "user exists with the given username", apiErr.Message)
+	assert.True(t, IsCode(err, xerror.AlreadyExists))
+}
+
+func TestClient_Retries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(up.GetMovieResponse{Movie: up.Movie{ID: "movie-id"}})
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithRetries(2, time.Millisecond))
+	ctx := context.Background()
+
+	resp, err := c.GetMovie(ctx, &up.GetMovieRequest{ID: "movie-id"})
+	require.NoError(t, err)
+	assert.Equal(t, "movie-id", resp.ID)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// creating a movie isn't idempotent so it's never retried
+	atomic.StoreInt32(&calls, 0)
+	_, err = c.CreateMovie(ctx, &up.CreateMovieRequest{Link: "https://www.youtube.com/watch?v=id"})
+	assert.True(t, IsCode(err, xerror.Unavailable))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClient_RefreshesExpiredToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/refresh":
+			var req up.RefreshRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, "old-refresh-token", req.RefreshToken)
+			json.NewEncoder(w).Encode(up.RefreshResponse{Token: "new-token", RefreshToken: "new-refresh-token"})
+		case "/api/v2/movies/movie-id":
+			if r.Header.Get("Authorization") != "Bearer new-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(up.DeleteMovieResponse{})
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithTokens("old-token", "old-refresh-token"))
+
+	_, err := c.DeleteMovie(context.Background(), &up.DeleteMovieRequest{ID: "movie-id"})
+	require.NoError(t, err)
+
+	token, refreshToken := c.Tokens()
+	assert.Equal(t, "new-token", token)
+	assert.Equal(t, "new-refresh-token", refreshToken)
+}
+
+func TestClient_RefreshesExpiredTokenOnce(t *testing.T) {
+	const concurrentCalls = 5
+
+	// the expired token is rejected once every call sent it, so that they all need a refresh
+	var rejected sync.WaitGroup
+	rejected.Add(concurrentCalls)
+	var refreshes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/refresh":
+			var req up.RefreshRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			// like the server, a refresh token can only be exchanged once
+			if req.RefreshToken != "old-refresh-token" || atomic.AddInt32(&refreshes, 1) > 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(up.RefreshResponse{Token: "new-token", RefreshToken: "new-refresh-token"})
+		case "/api/v2/movies/movie-id":
+			if r.Header.Get("Authorization") != "Bearer new-token" {
+				rejected.Done()
+				rejected.Wait()
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(up.DeleteMovieResponse{})
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithTokens("old-token", "old-refresh-token"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentCalls)
+	for i := 0; i < concurrentCalls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.DeleteMovie(context.Background(), &up.DeleteMovieRequest{ID: "movie-id"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&refreshes))
+	token, refreshToken := c.Tokens()
+	assert.Equal(t, "new-token", token)
+	assert.Equal(t, "new-refresh-token", refreshToken)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"remi/up"
+)
+
+func (c *Client) CreateComment(ctx context.Context, req *up.CreateCommentRequest) (*up.CreateCommentResponse, error) {
+	resp := &up.CreateCommentResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v2/movies/" + url.PathEscape(req.MovieID) + "/comments", body: req, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) EditComment(ctx context.Context, req *up.EditCommentRequest) (*up.EditCommentResponse, error) {
+	resp := &up.EditCommentResponse{}
+	if err := c.do(ctx, call{method: http.MethodPatch, path: "/api/v2/comments/" + url.PathEscape(req.ID), body: req, idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) DeleteComment(ctx context.Context, req *up.DeleteCommentRequest) (*up.DeleteCommentResponse, error) {
+	resp := &up.DeleteCommentResponse{}
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v2/comments/" + url.PathEscape(req.ID), idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ListComments(ctx context.Context, req *up.ListCommentsRequest) (*up.ListCommentsResponse, error) {
+	query := make(url.Values)
+	if req.Cursor != "" {
+		query.Set("cursor", req.Cursor)
+	}
+	if req.Limit != nil {
+		query.Set("limit", strconv.Itoa(*req.Limit))
+	}
+
+	resp := &up.ListCommentsResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/movies/" + url.PathEscape(req.MovieID) + "/comments", query: query}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ListReplies(ctx context.Context, req *up.ListRepliesRequest) (*up.ListRepliesResponse, error) {
+	query := make(url.Values)
+	if req.Cursor != "" {
+		query.Set("cursor", req.Cursor)
+	}
+	if req.Limit != nil {
+		query.Set("limit", strconv.Itoa(*req.Limit))
+	}
+
+	resp := &up.ListRepliesResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/comments/" + url.PathEscape(req.ID) + "/replies", query: query}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
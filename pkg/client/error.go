@@ -0,0 +1,73 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"remi/pkg/xerror"
+	"remi/up"
+)
+
+// Error is returned when the API answers with an error
+type Error struct {
+	StatusCode int
+	Code       xerror.Code
+	Message    string
+	Details    []xerror.FieldViolation
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("remi: %s", http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("remi: %s", e.Message)
+}
+
+// IsCode reports whether err is an API error with the code
+func IsCode(err error, code xerror.Code) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// mHttpStatusAndCode guesses the code of errors without body, e.g. from a proxy
+var mHttpStatusAndCode = map[int]xerror.Code{
+	http.StatusBadRequest:          xerror.InvalidArgument,
+	http.StatusUnauthorized:        xerror.UnAuthorized,
+	http.StatusForbidden:           xerror.PermissionDenied,
+	http.StatusNotFound:            xerror.NotFound,
+	http.StatusMethodNotAllowed:    xerror.MethodNotAllowed,
+	http.StatusConflict:            xerror.Conflict,
+	http.StatusTooManyRequests:     xerror.RateLimited,
+	http.StatusInternalServerError: xerror.Internal,
+	http.StatusServiceUnavailable:  xerror.Unavailable,
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Code:       xerror.Unknown,
+	}
+	if code, ok := mHttpStatusAndCode[resp.StatusCode]; ok {
+		apiErr.Code = code
+	}
+
+	// some errors, e.g. a missing token, have no body
+	data, err := io.ReadAll(resp.Body)
+	if err != nil || len(data) == 0 {
+		return apiErr
+	}
+
+	var errResp up.ErrorResponse
+	if err := json.Unmarshal(data, &errResp); err == nil {
+		apiErr.Message = errResp.Error
+		apiErr.Details = errResp.Details
+		if errResp.Code != "" {
+			apiErr.Code = xerror.ParseCode(errResp.Code)
+		}
+	}
+
+	return apiErr
+}
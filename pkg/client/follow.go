@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"remi/up"
+)
+
+func (c *Client) FollowUser(ctx context.Context, req *up.FollowUserRequest) (*up.FollowUserResponse, error) {
+	resp := &up.FollowUserResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/api/v2/users/" + url.PathEscape(req.UserID) + "/follow", idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) UnfollowUser(ctx context.Context, req *up.UnfollowUserRequest) (*up.UnfollowUserResponse, error) {
+	resp := &up.UnfollowUserResponse{}
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v2/users/" + url.PathEscape(req.UserID) + "/follow", idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ListFollowers(ctx context.Context, req *up.ListFollowersRequest) (*up.ListFollowersResponse, error) {
+	resp := &up.ListFollowersResponse{}
+	path := "/api/v2/users/" + url.PathEscape(req.UserID) + "/followers"
+	if err := c.do(ctx, call{method: http.MethodGet, path: path, query: cursorQuery(req.Cursor, req.Limit)}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ListFollowing(ctx context.Context, req *up.ListFollowingRequest) (*up.ListFollowingResponse, error) {
+	resp := &up.ListFollowingResponse{}
+	path := "/api/v2/users/" + url.PathEscape(req.UserID) + "/following"
+	if err := c.do(ctx, call{method: http.MethodGet, path: path, query: cursorQuery(req.Cursor, req.Limit)}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
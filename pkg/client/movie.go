@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"remi/up"
+)
+
+func (c *Client) CreateMovie(ctx context.Context, req *up.CreateMovieRequest) (*up.CreateMovieResponse, error) {
+	resp := &up.CreateMovieResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v2/movies", body: req, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) GetMovie(ctx context.Context, req *up.GetMovieRequest) (*up.GetMovieResponse, error) {
+	resp := &up.GetMovieResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/movies/" + url.PathEscape(req.ID), auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ListMovies(ctx context.Context, req *up.ListMoviesRequest) (*up.ListMoviesResponse, error) {
+	query := pagingQuery(req.Offset, req.Limit)
+	if req.Cursor != "" {
+		query.Set("cursor", req.Cursor)
+	}
+	if len(req.Tags) > 0 {
+		query["tags"] = req.Tags
+	}
+	if req.TagMode != "" {
+		query.Set("tag_mode", req.TagMode)
+	}
+
+	resp := &up.ListMoviesResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/movies", query: query, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ListMoviesByUsername lists the movies shared by a user with their public profile
+func (c *Client) ListMoviesByUsername(ctx context.Context, req *up.ListMoviesByUsernameRequest) (*up.ListMoviesByUsernameResponse, error) {
+	query := pagingQuery(req.Offset, req.Limit)
+	if req.Cursor != "" {
+		query.Set("cursor", req.Cursor)
+	}
+
+	resp := &up.ListMoviesByUsernameResponse{}
+	path := "/api/v2/u/" + url.PathEscape(req.Username) + "/movies"
+	if err := c.do(ctx, call{method: http.MethodGet, path: path, query: query, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ListFeed lists the movies shared by the users the logged in user follows
+func (c *Client) ListFeed(ctx context.Context, req *up.ListFeedRequest) (*up.ListFeedResponse, error) {
+	query := pagingQuery(req.Offset, req.Limit)
+	if req.Cursor != "" {
+		query.Set("cursor", req.Cursor)
+	}
+
+	resp := &up.ListFeedResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/feed", query: query, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ListMoviesByUser lists the movies shared by the logged in user
+func (c *Client) ListMoviesByUser(ctx context.Context, req *up.ListMoviesByUserRequest) (*up.ListMoviesByUserResponse, error) {
+	resp := &up.ListMoviesByUserResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/listMoviesByUser", body: req, idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) SearchMovies(ctx context.Context, req *up.SearchMoviesRequest) (*up.SearchMoviesResponse, error) {
+	query := pagingQuery(req.Offset, req.Limit)
+	query.Set("query", req.Query)
+
+	resp := &up.SearchMoviesResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/movies/search", query: query, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) UpdateMovie(ctx context.Context, req *up.UpdateMovieRequest) (*up.UpdateMovieResponse, error) {
+	resp := &up.UpdateMovieResponse{}
+	if err := c.do(ctx, call{method: http.MethodPatch, path: "/api/v2/movies/" + url.PathEscape(req.ID), body: req, idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) DeleteMovie(ctx context.Context, req *up.DeleteMovieRequest) (*up.DeleteMovieResponse, error) {
+	resp := &up.DeleteMovieResponse{}
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v2/movies/" + url.PathEscape(req.ID), idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ReportMovie(ctx context.Context, req *up.ReportMovieRequest) (*up.ReportMovieResponse, error) {
+	resp := &up.ReportMovieResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v2/movies/" + url.PathEscape(req.MovieID) + "/reports", body: req, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) LikeMovie(ctx context.Context, req *up.LikeMovieRequest) (*up.LikeMovieResponse, error) {
+	resp := &up.LikeMovieResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/api/v2/movies/" + url.PathEscape(req.MovieID) + "/like", idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) DislikeMovie(ctx context.Context, req *up.DislikeMovieRequest) (*up.DislikeMovieResponse, error) {
+	resp := &up.DislikeMovieResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/api/v2/movies/" + url.PathEscape(req.MovieID) + "/dislike", idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) UnvoteMovie(ctx context.Context, req *up.UnvoteMovieRequest) (*up.UnvoteMovieResponse, error) {
+	resp := &up.UnvoteMovieResponse{}
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v2/movies/" + url.PathEscape(req.MovieID) + "/vote", idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func pagingQuery(offset, limit *int) url.Values {
+	query := make(url.Values)
+	if offset != nil {
+		query.Set("offset", strconv.Itoa(*offset))
+	}
+	if limit != nil {
+		query.Set("limit", strconv.Itoa(*limit))
+	}
+
+	return query
+}
+
+func cursorQuery(cursor string, limit *int) url.Values {
+	query := make(url.Values)
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if limit != nil {
+		query.Set("limit", strconv.Itoa(*limit))
+	}
+
+	return query
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"remi/up"
+)
+
+func (c *Client) CreatePlaylist(ctx context.Context, req *up.CreatePlaylistRequest) (*up.CreatePlaylistResponse, error) {
+	resp := &up.CreatePlaylistResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v2/playlists", body: req, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) GetPlaylist(ctx context.Context, req *up.GetPlaylistRequest) (*up.GetPlaylistResponse, error) {
+	resp := &up.GetPlaylistResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/playlists/" + url.PathEscape(req.ID), auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ListPlaylists(ctx context.Context, req *up.ListPlaylistsRequest) (*up.ListPlaylistsResponse, error) {
+	query := make(url.Values)
+	if req.UserID != "" {
+		query.Set("user_id", req.UserID)
+	}
+	if req.Cursor != "" {
+		query.Set("cursor", req.Cursor)
+	}
+	if req.Limit != nil {
+		query.Set("limit", strconv.Itoa(*req.Limit))
+	}
+
+	resp := &up.ListPlaylistsResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/playlists", query: query, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) UpdatePlaylist(ctx context.Context, req *up.UpdatePlaylistRequest) (*up.UpdatePlaylistResponse, error) {
+	resp := &up.UpdatePlaylistResponse{}
+	if err := c.do(ctx, call{method: http.MethodPatch, path: "/api/v2/playlists/" + url.PathEscape(req.ID), body: req, idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) DeletePlaylist(ctx context.Context, req *up.DeletePlaylistRequest) (*up.DeletePlaylistResponse, error) {
+	resp := &up.DeletePlaylistResponse{}
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v2/playlists/" + url.PathEscape(req.ID), idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) AddPlaylistItem(ctx context.Context, req *up.AddPlaylistItemRequest) (*up.AddPlaylistItemResponse, error) {
+	resp := &up.AddPlaylistItemResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v2/playlists/" + url.PathEscape(req.PlaylistID) + "/items", body: req, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) RemovePlaylistItem(ctx context.Context, req *up.RemovePlaylistItemRequest) (*up.RemovePlaylistItemResponse, error) {
+	resp := &up.RemovePlaylistItemResponse{}
+	path := "/api/v2/playlists/" + url.PathEscape(req.PlaylistID) + "/items/" + url.PathEscape(req.MovieID)
+	if err := c.do(ctx, call{method: http.MethodDelete, path: path, idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ReorderPlaylistItems(ctx context.Context, req *up.ReorderPlaylistItemsRequest) (*up.ReorderPlaylistItemsResponse, error) {
+	resp := &up.ReorderPlaylistItemsResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/api/v2/playlists/" + url.PathEscape(req.PlaylistID) + "/items", body: req, idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
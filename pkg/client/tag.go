@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"remi/up"
+)
+
+func (c *Client) ListTags(ctx context.Context, req *up.ListTagsRequest) (*up.ListTagsResponse, error) {
+	query := make(url.Values)
+	if req.Limit != nil {
+		query.Set("limit", strconv.Itoa(*req.Limit))
+	}
+
+	resp := &up.ListTagsResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/tags", query: query}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
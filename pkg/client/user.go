@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"remi/up"
+)
+
+func (c *Client) Register(ctx context.Context, req *up.RegisterRequest) (*up.RegisterResponse, error) {
+	resp := &up.RegisterResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/register", body: req}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Login authenticates the following calls of the client
+func (c *Client) Login(ctx context.Context, req *up.LoginRequest) (*up.LoginResponse, error) {
+	resp := &up.LoginResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/login", body: req}, resp); err != nil {
+		return nil, err
+	}
+
+	c.setTokens(resp.Token, resp.RefreshToken)
+	return resp, nil
+}
+
+func (c *Client) Refresh(ctx context.Context, req *up.RefreshRequest) (*up.RefreshResponse, error) {
+	resp := &up.RefreshResponse{}
+	if err := c.doWithRetries(ctx, call{method: http.MethodPost, path: "/api/v1/refresh", body: req}, resp); err != nil {
+		return nil, err
+	}
+
+	c.setTokens(resp.Token, resp.RefreshToken)
+	return resp, nil
+}
+
+// Logout revokes the tokens of the client, the refresh token of the client is used when req doesn't set one
+func (c *Client) Logout(ctx context.Context, req *up.LogoutRequest) (*up.LogoutResponse, error) {
+	if req.RefreshToken == "" {
+		_, req.RefreshToken = c.Tokens()
+	}
+
+	resp := &up.LogoutResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/logout", body: req, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	c.setTokens("", "")
+	return resp, nil
+}
+
+func (c *Client) GetProfile(ctx context.Context) (*up.GetProfileResponse, error) {
+	resp := &up.GetProfileResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/me", auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) UpdateProfile(ctx context.Context, req *up.UpdateProfileRequest) (*up.UpdateProfileResponse, error) {
+	resp := &up.UpdateProfileResponse{}
+	if err := c.do(ctx, call{method: http.MethodPatch, path: "/api/v2/me", body: req, idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ChangePassword authenticates the following calls of the client with the new tokens
+func (c *Client) ChangePassword(ctx context.Context, req *up.ChangePasswordRequest) (*up.ChangePasswordResponse, error) {
+	resp := &up.ChangePasswordResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/api/v2/me/password", body: req, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	c.setTokens(resp.Token, resp.RefreshToken)
+	return resp, nil
+}
+
+func (c *Client) UploadAvatar(ctx context.Context, req *up.UploadAvatarRequest) (*up.UploadAvatarResponse, error) {
+	resp := &up.UploadAvatarResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/api/v2/me/avatar", body: req, idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) DeleteAvatar(ctx context.Context) (*up.DeleteAvatarResponse, error) {
+	resp := &up.DeleteAvatarResponse{}
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v2/me/avatar", idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
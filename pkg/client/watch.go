@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"remi/up"
+)
+
+func (c *Client) AddWatchLater(ctx context.Context, req *up.AddWatchLaterRequest) (*up.AddWatchLaterResponse, error) {
+	resp := &up.AddWatchLaterResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/api/v2/me/watch-later/" + url.PathEscape(req.MovieID), idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) RemoveWatchLater(ctx context.Context, req *up.RemoveWatchLaterRequest) (*up.RemoveWatchLaterResponse, error) {
+	resp := &up.RemoveWatchLaterResponse{}
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v2/me/watch-later/" + url.PathEscape(req.MovieID), idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ListWatchLater(ctx context.Context, req *up.ListWatchLaterRequest) (*up.ListWatchLaterResponse, error) {
+	resp := &up.ListWatchLaterResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/me/watch-later", query: cursorQuery(req.Cursor, req.Limit), auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) RecordWatch(ctx context.Context, req *up.RecordWatchRequest) (*up.RecordWatchResponse, error) {
+	resp := &up.RecordWatchResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/api/v2/me/history/" + url.PathEscape(req.MovieID), idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) RemoveWatchHistory(ctx context.Context, req *up.RemoveWatchHistoryRequest) (*up.RemoveWatchHistoryResponse, error) {
+	resp := &up.RemoveWatchHistoryResponse{}
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v2/me/history/" + url.PathEscape(req.MovieID), idempotent: true, auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ListWatchHistory(ctx context.Context, req *up.ListWatchHistoryRequest) (*up.ListWatchHistoryResponse, error) {
+	resp := &up.ListWatchHistoryResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/v2/me/history", query: cursorQuery(req.Cursor, req.Limit), auth: true}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
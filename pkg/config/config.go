@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"remi/pkg/cmsql"
 )
@@ -42,11 +43,53 @@ func (c HTTP) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// PasswordPolicy is checked when registering, BreachedPasswordsFile lists one password per line
+// and can be left empty
+type PasswordPolicy struct {
+	MinLength             int    `yaml:"min_length"`
+	MinCharClasses        int    `yaml:"min_char_classes"`
+	BreachedPasswordsFile string `yaml:"breached_passwords_file"`
+}
+
+// PasswordHashing selects the algorithm of new password hashes, bcrypt or argon2id,
+// the hashes made with another algorithm or other parameters are upgraded on login.
+// Argon2Memory is in KiB.
+type PasswordHashing struct {
+	Algorithm     string `yaml:"algorithm"`
+	BcryptCost    int    `yaml:"bcrypt_cost"`
+	Argon2Time    uint32 `yaml:"argon2_time"`
+	Argon2Memory  uint32 `yaml:"argon2_memory"`
+	Argon2Threads uint8  `yaml:"argon2_threads"`
+}
+
+// LoginLockout locks a username out after MaxAttempts failed logins in a row and an IP after
+// MaxAttemptsPerIP, for Duration which doubles with every further failure up to MaxDuration, without limit when it is 0
+type LoginLockout struct {
+	MaxAttempts      int           `yaml:"max_attempts"`
+	MaxAttemptsPerIP int           `yaml:"max_attempts_per_ip"`
+	Duration         time.Duration `yaml:"duration"`
+	MaxDuration      time.Duration `yaml:"max_duration"`
+}
+
 type Config struct {
 	*Postgres `yaml:"postgres"`
 	JWTSecret string `yaml:"jwt_secret"`
 	HTTP      HTTP   `yaml:"http"`
 	URL       string `yaml:"url"`
+	// AccessTokenTTL is the lifetime of JWTs, RefreshTokenTTL the lifetime of the tokens exchanged for new JWTs
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+	// MetadataEnrichment fetches name, author, duration and thumbnail of shared movies from their provider
+	MetadataEnrichment bool            `yaml:"metadata_enrichment"`
+	MetadataTimeout    time.Duration   `yaml:"metadata_timeout"`
+	PasswordPolicy     PasswordPolicy  `yaml:"password_policy"`
+	PasswordHashing    PasswordHashing `yaml:"password_hashing"`
+	LoginLockout       LoginLockout    `yaml:"login_lockout"`
+	// ClientIPHeader is set by the reverse proxy with the IP of the client, e.g. X-Real-IP,
+	// the IP of the connection is used when it's empty
+	ClientIPHeader string `yaml:"client_ip_header"`
+	// BlobDir is the directory where uploaded files such as avatars are stored
+	BlobDir string `yaml:"blob_dir"`
 }
 
 func Load() (cfg *Config, err error) {
@@ -60,6 +103,66 @@ func Load() (cfg *Config, err error) {
 		return nil, fmt.Errorf("HTTP_PORT must be number")
 	}
 	url := os.Getenv("URL")
+	accessTokenTTL, err := time.ParseDuration(Coalesce(os.Getenv("ACCESS_TOKEN_TTL"), "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("ACCESS_TOKEN_TTL must be duration")
+	}
+	refreshTokenTTL, err := time.ParseDuration(Coalesce(os.Getenv("REFRESH_TOKEN_TTL"), "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("REFRESH_TOKEN_TTL must be duration")
+	}
+	metadataEnrichment, err := strconv.ParseBool(Coalesce(os.Getenv("METADATA_ENRICHMENT"), "false"))
+	if err != nil {
+		return nil, fmt.Errorf("METADATA_ENRICHMENT must be boolean")
+	}
+	metadataTimeout, err := time.ParseDuration(Coalesce(os.Getenv("METADATA_TIMEOUT"), "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("METADATA_TIMEOUT must be duration")
+	}
+	passwordMinLength, err := strconv.Atoi(Coalesce(os.Getenv("PASSWORD_MIN_LENGTH"), "8"))
+	if err != nil {
+		return nil, fmt.Errorf("PASSWORD_MIN_LENGTH must be number")
+	}
+	passwordMinCharClasses, err := strconv.Atoi(Coalesce(os.Getenv("PASSWORD_MIN_CHAR_CLASSES"), "2"))
+	if err != nil {
+		return nil, fmt.Errorf("PASSWORD_MIN_CHAR_CLASSES must be number")
+	}
+	passwordHashAlgorithm := Coalesce(os.Getenv("PASSWORD_HASH_ALGORITHM"), "argon2id")
+	if passwordHashAlgorithm != "bcrypt" && passwordHashAlgorithm != "argon2id" {
+		return nil, fmt.Errorf("PASSWORD_HASH_ALGORITHM must be bcrypt or argon2id")
+	}
+	bcryptCost, err := strconv.Atoi(Coalesce(os.Getenv("BCRYPT_COST"), "12"))
+	if err != nil || bcryptCost < 4 || bcryptCost > 31 {
+		return nil, fmt.Errorf("BCRYPT_COST must be number between 4 and 31")
+	}
+	argon2Time, err := strconv.ParseUint(Coalesce(os.Getenv("ARGON2_TIME"), "2"), 10, 32)
+	if err != nil || argon2Time == 0 {
+		return nil, fmt.Errorf("ARGON2_TIME must be positive number")
+	}
+	argon2Memory, err := strconv.ParseUint(Coalesce(os.Getenv("ARGON2_MEMORY"), "19456"), 10, 32)
+	if err != nil || argon2Memory == 0 {
+		return nil, fmt.Errorf("ARGON2_MEMORY must be positive number")
+	}
+	argon2Threads, err := strconv.ParseUint(Coalesce(os.Getenv("ARGON2_THREADS"), "1"), 10, 8)
+	if err != nil || argon2Threads == 0 {
+		return nil, fmt.Errorf("ARGON2_THREADS must be positive number")
+	}
+	loginMaxAttempts, err := strconv.Atoi(Coalesce(os.Getenv("LOGIN_MAX_ATTEMPTS"), "5"))
+	if err != nil {
+		return nil, fmt.Errorf("LOGIN_MAX_ATTEMPTS must be number")
+	}
+	loginMaxAttemptsPerIP, err := strconv.Atoi(Coalesce(os.Getenv("LOGIN_MAX_ATTEMPTS_PER_IP"), "20"))
+	if err != nil {
+		return nil, fmt.Errorf("LOGIN_MAX_ATTEMPTS_PER_IP must be number")
+	}
+	loginLockoutDuration, err := time.ParseDuration(Coalesce(os.Getenv("LOGIN_LOCKOUT_DURATION"), "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("LOGIN_LOCKOUT_DURATION must be duration")
+	}
+	loginLockoutMaxDuration, err := time.ParseDuration(Coalesce(os.Getenv("LOGIN_LOCKOUT_MAX_DURATION"), "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("LOGIN_LOCKOUT_MAX_DURATION must be duration")
+	}
 
 	return &Config{
 		Postgres:  postgresCfg,
@@ -68,7 +171,31 @@ func Load() (cfg *Config, err error) {
 			Host: "",
 			Port: httpPort,
 		},
-		URL: url,
+		URL:                url,
+		AccessTokenTTL:     accessTokenTTL,
+		RefreshTokenTTL:    refreshTokenTTL,
+		MetadataEnrichment: metadataEnrichment,
+		MetadataTimeout:    metadataTimeout,
+		PasswordPolicy: PasswordPolicy{
+			MinLength:             passwordMinLength,
+			MinCharClasses:        passwordMinCharClasses,
+			BreachedPasswordsFile: os.Getenv("BREACHED_PASSWORDS_FILE"),
+		},
+		PasswordHashing: PasswordHashing{
+			Algorithm:     passwordHashAlgorithm,
+			BcryptCost:    bcryptCost,
+			Argon2Time:    uint32(argon2Time),
+			Argon2Memory:  uint32(argon2Memory),
+			Argon2Threads: uint8(argon2Threads),
+		},
+		LoginLockout: LoginLockout{
+			MaxAttempts:      loginMaxAttempts,
+			MaxAttemptsPerIP: loginMaxAttemptsPerIP,
+			Duration:         loginLockoutDuration,
+			MaxDuration:      loginLockoutMaxDuration,
+		},
+		ClientIPHeader: os.Getenv("CLIENT_IP_HEADER"),
+		BlobDir:        Coalesce(os.Getenv("BLOB_DIR"), "data/blobs"),
 	}, nil
 }
 
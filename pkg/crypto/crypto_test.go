@@ -6,15 +6,20 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 )
 
-func TestHashPassword(t *testing.T) {
-	Convey("HashPassword", t, func() {
-		rawPassword := "sample"
-		hashedPassword, err := HashPassword(rawPassword)
+func TestRandomToken(t *testing.T) {
+	Convey("RandomToken", t, func() {
+		token, err := RandomToken(32)
 		So(err, ShouldBeNil)
+		So(token, ShouldHaveLength, 43)
 
-		Convey("CheckHashPassword", func() {
-			check := CheckPasswordHash(rawPassword, hashedPassword)
-			So(check, ShouldBeTrue)
+		other, err := RandomToken(32)
+		So(err, ShouldBeNil)
+		So(other, ShouldNotEqual, token)
+
+		Convey("HashToken", func() {
+			So(HashToken(token), ShouldEqual, HashToken(token))
+			So(HashToken(token), ShouldNotEqual, HashToken(other))
+			So(HashToken(token), ShouldHaveLength, 64)
 		})
 	})
 }
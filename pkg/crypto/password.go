@@ -0,0 +1,183 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// PasswordHasher hashes passwords into PHC strings, e.g. $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>.
+// Verify accepts the hashes of every supported algorithm so the algorithm can be changed,
+// NeedsRehash reports the hashes made with another algorithm or other parameters.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) (bool, error)
+	NeedsRehash(hash string) bool
+}
+
+var (
+	_ PasswordHasher = &BcryptHasher{}
+	_ PasswordHasher = &Argon2idHasher{}
+)
+
+type BcryptHasher struct {
+	Cost int
+}
+
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{Cost: cost}
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt.GenerateFromPassword: %w", err)
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Verify(password, hash string) (bool, error) {
+	return verifyPassword(password, hash)
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	if !isBcrypt(hash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2idHasher Memory is in KiB
+type Argon2idHasher struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+func NewArgon2idHasher(time, memory uint32, threads uint8) *Argon2idHasher {
+	return &Argon2idHasher{
+		Time:    time,
+		Memory:  memory,
+		Threads: threads,
+	}
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("rand.Read: %w", err)
+	}
+
+	params := &argon2Params{
+		Time:    h.Time,
+		Memory:  h.Memory,
+		Threads: h.Threads,
+		Salt:    salt,
+	}
+	params.Key = argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, argon2KeyLength)
+	return params.String(), nil
+}
+
+func (h *Argon2idHasher) Verify(password, hash string) (bool, error) {
+	return verifyPassword(password, hash)
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, err := parseArgon2(hash)
+	if err != nil {
+		return true
+	}
+	return params.Time != h.Time || params.Memory != h.Memory || params.Threads != h.Threads ||
+		len(params.Salt) != argon2SaltLength || len(params.Key) != argon2KeyLength
+}
+
+// verifyPassword checks the password against a hash of any supported algorithm
+func verifyPassword(password, hash string) (bool, error) {
+	switch {
+	case isBcrypt(hash):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("bcrypt.CompareHashAndPassword: %w", err)
+		}
+		return true, nil
+	case strings.HasPrefix(hash, "$"+AlgorithmArgon2id+"$"):
+		params, err := parseArgon2(hash)
+		if err != nil {
+			return false, err
+		}
+		key := argon2.IDKey([]byte(password), params.Salt, params.Time, params.Memory, params.Threads, uint32(len(params.Key)))
+		return subtle.ConstantTimeCompare(key, params.Key) == 1, nil
+	}
+
+	return false, ErrUnknownHashFormat
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+type argon2Params struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	Salt    []byte
+	Key     []byte
+}
+
+func (p *argon2Params) String() string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", AlgorithmArgon2id, argon2.Version, p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(p.Salt), base64.RawStdEncoding.EncodeToString(p.Key))
+}
+
+// parseArgon2 parses $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+func parseArgon2(hash string) (*argon2Params, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != AlgorithmArgon2id {
+		return nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	params := &argon2Params{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	var err error
+	if params.Salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	if params.Key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	return params, nil
+}
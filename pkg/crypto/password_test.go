@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBcryptHasher(t *testing.T) {
+	Convey("BcryptHasher", t, func() {
+		hasher := NewBcryptHasher(4)
+		hash, err := hasher.Hash("sample")
+		So(err, ShouldBeNil)
+		So(hash, ShouldStartWith, "$2a$04$")
+
+		Convey("Verify", func() {
+			ok, err := hasher.Verify("sample", hash)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			ok, err = hasher.Verify("other", hash)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("NeedsRehash", func() {
+			So(hasher.NeedsRehash(hash), ShouldBeFalse)
+			So(NewBcryptHasher(5).NeedsRehash(hash), ShouldBeTrue)
+			So(NewArgon2idHasher(1, 64, 1).NeedsRehash(hash), ShouldBeTrue)
+		})
+	})
+}
+
+func TestArgon2idHasher(t *testing.T) {
+	Convey("Argon2idHasher", t, func() {
+		hasher := NewArgon2idHasher(1, 64, 1)
+		hash, err := hasher.Hash("sample")
+		So(err, ShouldBeNil)
+		So(hash, ShouldStartWith, "$argon2id$v=19$m=64,t=1,p=1$")
+		So(strings.Split(hash, "$"), ShouldHaveLength, 6)
+
+		other, err := hasher.Hash("sample")
+		So(err, ShouldBeNil)
+		So(other, ShouldNotEqual, hash)
+
+		Convey("Verify", func() {
+			ok, err := hasher.Verify("sample", hash)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			ok, err = hasher.Verify("other", hash)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Verify hashes of other algorithms", func() {
+			bcryptHash, err := NewBcryptHasher(4).Hash("sample")
+			So(err, ShouldBeNil)
+
+			ok, err := hasher.Verify("sample", bcryptHash)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("Verify malformed hashes", func() {
+			_, err := hasher.Verify("sample", "plain")
+			So(err, ShouldEqual, ErrUnknownHashFormat)
+
+			_, err = hasher.Verify("sample", "$argon2id$v=19$m=64,t=1,p=1$%%%$key")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("NeedsRehash", func() {
+			So(hasher.NeedsRehash(hash), ShouldBeFalse)
+			So(NewArgon2idHasher(2, 64, 1).NeedsRehash(hash), ShouldBeTrue)
+			So(NewArgon2idHasher(1, 128, 1).NeedsRehash(hash), ShouldBeTrue)
+			So(NewBcryptHasher(4).NeedsRehash(hash), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package crypto
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy describes acceptable passwords, character classes are lowercase letters,
+// uppercase letters, digits and symbols
+type PasswordPolicy struct {
+	MinLength      int
+	MinCharClasses int
+	// breached are lowercased passwords known from data breaches
+	breached map[string]struct{}
+}
+
+// LoadBreached reads passwords from r, one per line, and rejects them afterwards.
+// Empty lines and lines starting with # are skipped, passwords are compared case-insensitively.
+func (p *PasswordPolicy) LoadBreached(r io.Reader) error {
+	if p.breached == nil {
+		p.breached = make(map[string]struct{})
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.breached[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scanner.Err: %w", err)
+	}
+
+	return nil
+}
+
+// Check returns an error describing why the password doesn't satisfy the policy, or nil.
+// The message completes "password ...", e.g. "must be at least 8 characters".
+func (p *PasswordPolicy) Check(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("must be at least %d characters", p.MinLength)
+	}
+	if charClasses(password) < p.MinCharClasses {
+		return fmt.Errorf("must contain %d of lowercase letters, uppercase letters, digits and symbols", p.MinCharClasses)
+	}
+	if _, ok := p.breached[strings.ToLower(password)]; ok {
+		return fmt.Errorf("is too common, it appeared in a data breach")
+	}
+
+	return nil
+}
+
+func charClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
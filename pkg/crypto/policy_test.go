@@ -0,0 +1,34 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	Convey("PasswordPolicy", t, func() {
+		policy := &PasswordPolicy{MinLength: 8, MinCharClasses: 3}
+		err := policy.LoadBreached(strings.NewReader("# common passwords\nPassword-1\n\nqwerty-123\n"))
+		So(err, ShouldBeNil)
+
+		Convey("accepts strong passwords", func() {
+			So(policy.Check("correct-Horse-battery"), ShouldBeNil)
+			So(policy.Check("mật-khẩu-2023"), ShouldBeNil)
+		})
+
+		Convey("rejects short passwords", func() {
+			So(policy.Check("aB-1"), ShouldBeError, "must be at least 8 characters")
+		})
+
+		Convey("rejects passwords with too few character classes", func() {
+			So(policy.Check("onlylowercase1"), ShouldBeError, "must contain 3 of lowercase letters, uppercase letters, digits and symbols")
+		})
+
+		Convey("rejects breached passwords case-insensitively", func() {
+			So(policy.Check("password-1"), ShouldBeError, "is too common, it appeared in a data breach")
+			So(policy.Check("QWERTY-123"), ShouldBeError, "is too common, it appeared in a data breach")
+		})
+	})
+}
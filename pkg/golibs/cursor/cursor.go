@@ -0,0 +1,44 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor points to a row of a keyset paginated listing ordered by (created_at, id)
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the opaque representation of the cursor sent to clients
+func Encode(c *Cursor) string {
+	raw := fmt.Sprintf("%d|%s", c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Decode parses a cursor produced by Encode
+func Decode(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &Cursor{
+		CreatedAt: time.Unix(0, nanos),
+		ID:        parts[1],
+	}, nil
+}
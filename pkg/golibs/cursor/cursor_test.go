@@ -0,0 +1,25 @@
+package cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	c := &Cursor{
+		CreatedAt: time.Unix(0, 1666000000123456789),
+		ID:        "cdb2b7m5ehsc73ejfrqg",
+	}
+
+	decoded, err := Decode(Encode(c))
+	assert.NoError(t, err)
+	assert.True(t, c.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, c.ID, decoded.ID)
+
+	for _, invalid := range []string{"", "not base64!", Encode(&Cursor{ID: ""}), "MTIz"} {
+		_, err := Decode(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,15 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = pq.ErrorCode("23505")
+
+// IsUniqueViolation reports whether err was caused by a unique constraint, e.g. a duplicated username
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
@@ -0,0 +1,200 @@
+// Package openapi describes an API with the OpenAPI 3 specification and generates
+// the schemas of Go types from their json tags.
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps lowercase http methods to their operation
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Content map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// SecurityRequirement maps the name of a security scheme to its scopes, an empty requirement makes the security optional
+type SecurityRequirement map[string][]string
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Field is a property of a struct as it's encoded by encoding/json
+type Field struct {
+	Name string
+	Tags reflect.StructTag
+	Type reflect.Type
+}
+
+// NewDocument creates an empty document
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]*PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+		},
+	}
+}
+
+// SchemaOf returns the schema of typ, named structs are added to the components of the document
+// and referenced
+func (d *Document) SchemaOf(typ reflect.Type) *Schema {
+	if typ == reflect.TypeOf(time.Time{}) {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		schema := d.SchemaOf(typ.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.SchemaOf(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.SchemaOf(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return d.structSchema(typ)
+		}
+		if _, ok := d.Components.Schemas[typ.Name()]; !ok {
+			// registered before generating the properties so recursive types terminate
+			d.Components.Schemas[typ.Name()] = &Schema{}
+			*d.Components.Schemas[typ.Name()] = *d.structSchema(typ)
+		}
+		return &Schema{Ref: "#/components/schemas/" + typ.Name()}
+	default:
+		return &Schema{}
+	}
+}
+
+func (d *Document) structSchema(typ reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	for _, field := range Fields(typ) {
+		schema.Properties[field.Name] = d.SchemaOf(field.Type)
+	}
+
+	return schema
+}
+
+// Fields lists the properties of a struct, fields of embedded structs are promoted like encoding/json does
+func Fields(typ reflect.Type) []Field {
+	var fields []Field
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, Fields(embedded)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, Field{
+			Name: name,
+			Tags: f.Tag,
+			Type: f.Type,
+		})
+	}
+
+	return fields
+}
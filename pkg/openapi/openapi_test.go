@@ -0,0 +1,51 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testItem struct {
+	ID string `json:"id"`
+}
+
+type testEmbedded struct {
+	Name string `json:"name"`
+}
+
+type testRequest struct {
+	testEmbedded
+	Limit     *int              `json:"limit"`
+	CreatedAt time.Time         `json:"created_at"`
+	Items     []*testItem       `json:"items"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Ignored   string            `json:"-"`
+	internal  string
+}
+
+func TestDocument_SchemaOf(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+
+	schema := doc.SchemaOf(reflect.TypeOf(testRequest{}))
+
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/testRequest"}, schema)
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name":       {Type: "string"},
+			"limit":      {Type: "integer", Format: "int32", Nullable: true},
+			"created_at": {Type: "string", Format: "date-time"},
+			"items":      {Type: "array", Items: &Schema{Ref: "#/components/schemas/testItem"}},
+			"labels":     {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		},
+	}, doc.Components.Schemas["testRequest"])
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id": {Type: "string"},
+		},
+	}, doc.Components.Schemas["testItem"])
+}
@@ -0,0 +1,271 @@
+// Package validator validates structs declaratively with validate tags:
+//
+//	type ListMoviesRequest struct {
+//		Query string `json:"query" validate:"required,max=200"`
+//		Limit *int   `json:"limit" validate:"min=1,max=100"`
+//	}
+//
+// Rules are separated by commas:
+//   - required: strings can't be blank, pointers, slices and maps can't be nil or empty
+//   - notblank: strings can't be blank when they are set, i.e. non-nil *string or non-empty string
+//   - min=N, max=N: length of strings (in characters), slices (in bytes for []byte) and maps, value of numbers
+//   - url: an absolute http or https URL
+//   - oneof=a b c: one of the values separated by spaces
+//   - pattern=name: matches the regular expression registered with RegisterPattern
+//
+// Fields are named after their json tag. Nil pointers and empty strings are only checked by required,
+// the other rules apply to values which are set.
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"remi/pkg/xerror"
+)
+
+type pattern struct {
+	re          *regexp.Regexp
+	description string
+}
+
+var (
+	patternsMu sync.RWMutex
+	patterns   = make(map[string]*pattern)
+
+	// rulesCache maps struct types to their []fieldRules
+	rulesCache sync.Map
+)
+
+// RegisterPattern makes the regular expression usable as pattern=name, description explains
+// the expected format to clients, e.g. "can only contain letters and digits"
+func RegisterPattern(name string, re *regexp.Regexp, description string) {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+	patterns[name] = &pattern{re: re, description: description}
+}
+
+type fieldRules struct {
+	index    []int
+	name     string
+	required bool
+	notBlank bool
+	min      *float64
+	max      *float64
+	url      bool
+	oneOf    []string
+	pattern  string
+}
+
+// Validate checks every field of v, a struct or a pointer to a struct, and returns all the violations
+// as an InvalidArgument xerror.XError with details, or nil
+func Validate(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var violations []xerror.FieldViolation
+	for _, rules := range rulesOf(rv.Type()) {
+		if description := rules.check(rv.FieldByIndex(rules.index)); description != "" {
+			violations = append(violations, xerror.FieldViolation{
+				Field:       rules.name,
+				Description: description,
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.Field+" "+violation.Description)
+	}
+	return xerror.ErrorM(xerror.InvalidArgument, nil, strings.Join(messages, "; ")).WithDetails(violations...)
+}
+
+func rulesOf(typ reflect.Type) []*fieldRules {
+	if rules, ok := rulesCache.Load(typ); ok {
+		return rules.([]*fieldRules)
+	}
+
+	rules := parseStruct(typ, nil)
+	rulesCache.Store(typ, rules)
+	return rules
+}
+
+// parseStruct panics on invalid tags, they are programming errors
+func parseStruct(typ reflect.Type, index []int) []*fieldRules {
+	var result []*fieldRules
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			result = append(result, parseStruct(f.Type, fieldIndex)...)
+			continue
+		}
+
+		tag := f.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			name = f.Name
+		}
+
+		rules := &fieldRules{index: fieldIndex, name: name}
+		for _, rule := range strings.Split(tag, ",") {
+			key, value, _ := strings.Cut(rule, "=")
+			switch key {
+			case "required":
+				rules.required = true
+			case "notblank":
+				rules.notBlank = true
+			case "min":
+				rules.min = parseNumber(typ, f, value)
+			case "max":
+				rules.max = parseNumber(typ, f, value)
+			case "url":
+				rules.url = true
+			case "oneof":
+				rules.oneOf = strings.Fields(value)
+			case "pattern":
+				patternsMu.RLock()
+				_, ok := patterns[value]
+				patternsMu.RUnlock()
+				if !ok {
+					panic(fmt.Sprintf("validator: %s.%s: pattern %q isn't registered", typ, f.Name, value))
+				}
+				rules.pattern = value
+			default:
+				panic(fmt.Sprintf("validator: %s.%s: unknown rule %q", typ, f.Name, rule))
+			}
+		}
+		result = append(result, rules)
+	}
+
+	return result
+}
+
+func parseNumber(typ reflect.Type, f reflect.StructField, value string) *float64 {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validator: %s.%s: %q isn't a number", typ, f.Name, value))
+	}
+	return &n
+}
+
+// check returns the description of the first violated rule, or an empty string
+func (r *fieldRules) check(v reflect.Value) string {
+	set := false
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if r.required {
+				return "can't be null"
+			}
+			return ""
+		}
+		v = v.Elem()
+		set = true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return r.checkString(v.String(), set || v.Len() > 0)
+	case reflect.Slice, reflect.Map, reflect.Array:
+		if v.Len() == 0 && r.required {
+			return "can't be empty"
+		}
+		unit := "items"
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			unit = "bytes"
+		}
+		return r.checkRange(float64(v.Len()), unit)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return r.checkRange(float64(v.Int()), "")
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return r.checkRange(float64(v.Uint()), "")
+	case reflect.Float32, reflect.Float64:
+		return r.checkRange(v.Float(), "")
+	}
+
+	return ""
+}
+
+// checkString checks s, set is false for empty strings which aren't behind a pointer
+func (r *fieldRules) checkString(s string, set bool) string {
+	if strings.TrimSpace(s) == "" {
+		if r.required || (r.notBlank && set) {
+			return "can't be null"
+		}
+		return ""
+	}
+
+	if description := r.checkRange(float64(utf8.RuneCountInString(s)), "characters"); description != "" {
+		return description
+	}
+	if r.url {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "must be a valid URL"
+		}
+	}
+	if len(r.oneOf) > 0 && !contains(r.oneOf, s) {
+		return "must be one of " + strings.Join(r.oneOf, ", ")
+	}
+	if r.pattern != "" {
+		patternsMu.RLock()
+		p := patterns[r.pattern]
+		patternsMu.RUnlock()
+		if !p.re.MatchString(s) {
+			return p.description
+		}
+	}
+
+	return ""
+}
+
+// checkRange checks min and max, unit is empty for numbers
+func (r *fieldRules) checkRange(n float64, unit string) string {
+	tooSmall := r.min != nil && n < *r.min
+	tooLarge := r.max != nil && n > *r.max
+	if !tooSmall && !tooLarge {
+		return ""
+	}
+
+	switch {
+	case unit == "" && r.min != nil && r.max != nil:
+		return fmt.Sprintf("must be between %s and %s", formatNumber(*r.min), formatNumber(*r.max))
+	case unit == "" && tooSmall:
+		return fmt.Sprintf("must be at least %s", formatNumber(*r.min))
+	case unit == "":
+		return fmt.Sprintf("must be at most %s", formatNumber(*r.max))
+	case tooSmall:
+		return fmt.Sprintf("must contain at least %s %s", formatNumber(*r.min), unit)
+	default:
+		return fmt.Sprintf("can't be longer than %s %s", formatNumber(*r.max), unit)
+	}
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package validator
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"remi/pkg/xerror"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	RegisterPattern("test_slug", regexp.MustCompile(`^[a-z0-9-]+$`), "can only contain lowercase letters, digits and '-'")
+}
+
+type testPaging struct {
+	Offset *int `json:"offset" validate:"min=0"`
+	Limit  *int `json:"limit" validate:"min=1,max=100"`
+}
+
+type testRequest struct {
+	testPaging
+	Name     string   `json:"name" validate:"required,max=5"`
+	Title    *string  `json:"title" validate:"notblank"`
+	Link     string   `json:"link" validate:"url"`
+	Role     string   `json:"role" validate:"oneof=user admin"`
+	Slug     string   `json:"slug" validate:"pattern=test_slug"`
+	Tags     []string `json:"tags" validate:"max=2"`
+	Count    int      `json:"count" validate:"min=1"`
+	Data     []byte   `json:"data" validate:"max=4"`
+	Untagged string   `json:"untagged"`
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func validRequest() *testRequest {
+	return &testRequest{Name: "name", Count: 1}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		req        func(r *testRequest)
+		violations []xerror.FieldViolation
+	}{
+		{
+			name: "valid",
+			req: func(r *testRequest) {
+				r.Offset, r.Limit = intPtr(0), intPtr(100)
+				r.Title = stringPtr("title")
+				r.Link = "https://www.youtube.com/watch?v=1"
+				r.Role = "admin"
+				r.Slug = "a-slug"
+				r.Tags = []string{"a", "b"}
+			},
+		},
+		{
+			name: "blank required string",
+			req:  func(r *testRequest) { r.Name = "  " },
+			violations: []xerror.FieldViolation{
+				{Field: "name", Description: "can't be null"},
+			},
+		},
+		{
+			name: "string length in characters",
+			req:  func(r *testRequest) { r.Name = "ééééé" },
+		},
+		{
+			name: "string too long",
+			req:  func(r *testRequest) { r.Name = "éééééé" },
+			violations: []xerror.FieldViolation{
+				{Field: "name", Description: "can't be longer than 5 characters"},
+			},
+		},
+		{
+			name: "blank optional string",
+			req:  func(r *testRequest) { r.Title = stringPtr(" ") },
+			violations: []xerror.FieldViolation{
+				{Field: "title", Description: "can't be null"},
+			},
+		},
+		{
+			name: "empty optional string",
+			req:  func(r *testRequest) { r.Title = stringPtr("") },
+			violations: []xerror.FieldViolation{
+				{Field: "title", Description: "can't be null"},
+			},
+		},
+		{
+			name: "numeric ranges of embedded fields",
+			req:  func(r *testRequest) { r.Offset, r.Limit = intPtr(-1), intPtr(101) },
+			violations: []xerror.FieldViolation{
+				{Field: "offset", Description: "must be at least 0"},
+				{Field: "limit", Description: "must be between 1 and 100"},
+			},
+		},
+		{
+			name: "url, enum, pattern and slice length",
+			req: func(r *testRequest) {
+				r.Link = "www.youtube.com"
+				r.Role = "owner"
+				r.Slug = "A Slug"
+				r.Tags = []string{"a", "b", "c"}
+				r.Data = []byte("data!")
+			},
+			violations: []xerror.FieldViolation{
+				{Field: "link", Description: "must be a valid URL"},
+				{Field: "role", Description: "must be one of user, admin"},
+				{Field: "slug", Description: "can only contain lowercase letters, digits and '-'"},
+				{Field: "tags", Description: "can't be longer than 2 items"},
+				{Field: "data", Description: "can't be longer than 4 bytes"},
+			},
+		},
+		{
+			name: "non pointer number",
+			req:  func(r *testRequest) { r.Count = 0 },
+			violations: []xerror.FieldViolation{
+				{Field: "count", Description: "must be at least 1"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := validRequest()
+			testCase.req(req)
+
+			err := Validate(req)
+			if len(testCase.violations) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var xerr xerror.XError
+			require.True(t, errors.As(err, &xerr))
+			assert.Equal(t, http.StatusBadRequest, xerr.HttpStatus())
+			assert.Equal(t, testCase.violations, xerr.Details)
+			for _, violation := range testCase.violations {
+				assert.Contains(t, xerr.Message, violation.Field+" "+violation.Description)
+			}
+		})
+	}
+}
+
+func TestValidate_AllViolations(t *testing.T) {
+	err := Validate(&testRequest{})
+
+	var xerr xerror.XError
+	require.True(t, errors.As(err, &xerr))
+	assert.Equal(t, "name can't be null; count must be at least 1", xerr.Message)
+	assert.Len(t, xerr.Details, 2)
+}
+
+func TestValidate_InvalidTag(t *testing.T) {
+	type request struct {
+		Name string `json:"name" validate:"requird"`
+	}
+
+	assert.PanicsWithValue(t, `validator: validator.request.Name: unknown rule "requird"`, func() {
+		_ = Validate(&request{})
+	})
+	assert.Panics(t, func() {
+		_ = Validate(&struct {
+			Slug string `validate:"pattern=unknown"`
+		}{})
+	})
+}
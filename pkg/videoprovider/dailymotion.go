@@ -0,0 +1,59 @@
+package videoprovider
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var dailymotionIDRegexp = regexp.MustCompile(`^x[A-Za-z0-9]+$`)
+
+// Dailymotion supports dailymotion.com/video/{id} and dai.ly/{id} links
+type Dailymotion struct{}
+
+func (p *Dailymotion) Name() string {
+	return "dailymotion"
+}
+
+func (p *Dailymotion) Match(u *url.URL) (string, bool) {
+	var videoID string
+	segments := pathSegments(u)
+
+	switch {
+	case hostIs(u.Host, "dai.ly"):
+		if len(segments) > 0 {
+			videoID = segments[0]
+		}
+	case hostIs(u.Host, "dailymotion.com"):
+		for i := 0; i < len(segments)-1; i++ {
+			if segments[i] == "video" {
+				videoID = segments[i+1]
+				break
+			}
+		}
+	}
+
+	// old links append a slug to the id: /video/x7tgad0_title-of-the-video
+	videoID = strings.SplitN(videoID, "_", 2)[0]
+	if !dailymotionIDRegexp.MatchString(videoID) {
+		return "", false
+	}
+	return videoID, true
+}
+
+func (p *Dailymotion) WatchURL(videoID string) string {
+	return fmt.Sprintf("https://www.dailymotion.com/video/%s", videoID)
+}
+
+func (p *Dailymotion) ThumbnailURL(videoID string) string {
+	return fmt.Sprintf("https://www.dailymotion.com/thumbnail/video/%s", videoID)
+}
+
+func (p *Dailymotion) EmbedURL(videoID string) string {
+	return fmt.Sprintf("https://www.dailymotion.com/embed/video/%s", videoID)
+}
+
+func (p *Dailymotion) OEmbedEndpoint() string {
+	return "https://www.dailymotion.com/services/oembed"
+}
@@ -0,0 +1,96 @@
+package videoprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Metadata describes a video as reported by its provider
+type Metadata struct {
+	Title       string
+	Description string
+	Author      string
+	// Duration in seconds, 0 when the provider doesn't report it
+	Duration  int
+	Thumbnail string
+}
+
+// MetadataFetcher looks up the metadata of a resolved video
+type MetadataFetcher interface {
+	FetchMetadata(ctx context.Context, link string, video *Video) (*Metadata, error)
+}
+
+// HTTPClient is satisfied by *http.Client and can be stubbed in tests
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+var _ MetadataFetcher = &OEmbedFetcher{}
+
+// OEmbedFetcher fetches metadata from the oEmbed endpoint of the video provider
+type OEmbedFetcher struct {
+	client   HTTPClient
+	resolver *Resolver
+}
+
+func NewOEmbedFetcher(client HTTPClient, resolver *Resolver) *OEmbedFetcher {
+	return &OEmbedFetcher{
+		client:   client,
+		resolver: resolver,
+	}
+}
+
+type oEmbedResponse struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	AuthorName   string `json:"author_name"`
+	Duration     int    `json:"duration"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+func (f *OEmbedFetcher) FetchMetadata(ctx context.Context, link string, video *Video) (*Metadata, error) {
+	provider, ok := f.resolver.Provider(video.Provider)
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %s", video.Provider)
+	}
+
+	endpoint := provider.OEmbedEndpoint()
+	if endpoint == "" {
+		return nil, fmt.Errorf("provider %s doesn't support oEmbed", video.Provider)
+	}
+
+	query := url.Values{}
+	query.Set("url", link)
+	query.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("f.client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oEmbed endpoint returned status %d", resp.StatusCode)
+	}
+
+	var oEmbed oEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&oEmbed); err != nil {
+		return nil, fmt.Errorf("json.Decode: %w", err)
+	}
+
+	return &Metadata{
+		Title:       oEmbed.Title,
+		Description: oEmbed.Description,
+		Author:      oEmbed.AuthorName,
+		Duration:    oEmbed.Duration,
+		Thumbnail:   oEmbed.ThumbnailURL,
+	}, nil
+}
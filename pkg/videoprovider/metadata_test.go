@@ -0,0 +1,81 @@
+package videoprovider
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubHTTPClient struct {
+	do func(req *http.Request) (*http.Response, error)
+}
+
+func (c *stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return c.do(req)
+}
+
+func stubResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestOEmbedFetcher_FetchMetadata(t *testing.T) {
+	resolver := DefaultResolver()
+	link := "https://vimeo.com/76979871"
+	video, err := resolver.Resolve(link)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		do               func(req *http.Request) (*http.Response, error)
+		expectedMetadata *Metadata
+		expectedErr      error
+	}{
+		{
+			name: "happy case",
+			do: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "https://vimeo.com/api/oembed.json?format=json&url=https%3A%2F%2Fvimeo.com%2F76979871", req.URL.String())
+				return stubResponse(http.StatusOK, `{"title":"title","description":"description","author_name":"author","duration":62,"thumbnail_url":"https://i.vimeocdn.com/video/1.jpg"}`), nil
+			},
+			expectedMetadata: &Metadata{
+				Title:       "title",
+				Description: "description",
+				Author:      "author",
+				Duration:    62,
+				Thumbnail:   "https://i.vimeocdn.com/video/1.jpg",
+			},
+		},
+		{
+			name: "request error",
+			do: func(req *http.Request) (*http.Response, error) {
+				return nil, fmt.Errorf("timeout")
+			},
+			expectedErr: fmt.Errorf("f.client.Do: %w", fmt.Errorf("timeout")),
+		},
+		{
+			name: "not found",
+			do: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(http.StatusNotFound, ""), nil
+			},
+			expectedErr: fmt.Errorf("oEmbed endpoint returned status 404"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		fetcher := NewOEmbedFetcher(&stubHTTPClient{do: testCase.do}, resolver)
+		metadata, err := fetcher.FetchMetadata(context.Background(), link, video)
+		if testCase.expectedErr != nil {
+			assert.Equal(t, testCase.expectedErr.Error(), err.Error(), testCase.name)
+		} else {
+			assert.NoError(t, err, testCase.name)
+			assert.Equal(t, testCase.expectedMetadata, metadata, testCase.name)
+		}
+	}
+}
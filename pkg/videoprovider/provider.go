@@ -0,0 +1,105 @@
+package videoprovider
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// VideoProvider resolves links of a video hosting site
+type VideoProvider interface {
+	// Name is persisted along with the movie to identify its provider
+	Name() string
+	// Match returns the canonical video ID when the link belongs to the provider
+	Match(u *url.URL) (videoID string, ok bool)
+	// WatchURL is the canonical page of the video
+	WatchURL(videoID string) string
+	ThumbnailURL(videoID string) string
+	EmbedURL(videoID string) string
+	// OEmbedEndpoint is used to fetch the video metadata, empty when unsupported
+	OEmbedEndpoint() string
+}
+
+// Video is a link resolved by a VideoProvider
+type Video struct {
+	Provider  string
+	ID        string
+	URL       string
+	Thumbnail string
+	EmbedURL  string
+}
+
+type Resolver struct {
+	providers []VideoProvider
+}
+
+func NewResolver(providers ...VideoProvider) *Resolver {
+	return &Resolver{
+		providers: providers,
+	}
+}
+
+// DefaultResolver supports every provider of this package
+func DefaultResolver() *Resolver {
+	return NewResolver(
+		&YouTube{},
+		&Vimeo{},
+		&Dailymotion{},
+	)
+}
+
+// Resolve finds the provider of the link and returns the video it points to
+func (r *Resolver) Resolve(link string) (*Video, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return nil, fmt.Errorf("url.Parse: %w", err)
+	}
+
+	for _, p := range r.providers {
+		videoID, ok := p.Match(u)
+		if !ok {
+			continue
+		}
+		return &Video{
+			Provider:  p.Name(),
+			ID:        videoID,
+			URL:       p.WatchURL(videoID),
+			Thumbnail: p.ThumbnailURL(videoID),
+			EmbedURL:  p.EmbedURL(videoID),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported video link")
+}
+
+// Provider returns the provider registered with the given name
+func (r *Resolver) Provider(name string) (VideoProvider, bool) {
+	for _, p := range r.providers {
+		if p.Name() == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// hostIs reports whether host is domain or one of its subdomains
+func hostIs(host string, domains ...string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathSegments splits the url path into its non-empty segments
+func pathSegments(u *url.URL) []string {
+	var segments []string
+	for _, s := range strings.Split(u.Path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
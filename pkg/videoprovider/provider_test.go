@@ -0,0 +1,111 @@
+package videoprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver := DefaultResolver()
+
+	testCases := []struct {
+		name          string
+		link          string
+		expectedVideo *Video
+		expectedErr   bool
+	}{
+		{
+			name: "youtube watch link",
+			link: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42",
+			expectedVideo: &Video{
+				Provider:  "youtube",
+				ID:        "dQw4w9WgXcQ",
+				URL:       "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+				Thumbnail: "https://img.youtube.com/vi/dQw4w9WgXcQ/0.jpg",
+				EmbedURL:  "https://www.youtube.com/embed/dQw4w9WgXcQ",
+			},
+		},
+		{
+			name: "youtube short link",
+			link: "https://youtu.be/dQw4w9WgXcQ",
+			expectedVideo: &Video{
+				Provider:  "youtube",
+				ID:        "dQw4w9WgXcQ",
+				URL:       "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+				Thumbnail: "https://img.youtube.com/vi/dQw4w9WgXcQ/0.jpg",
+				EmbedURL:  "https://www.youtube.com/embed/dQw4w9WgXcQ",
+			},
+		},
+		{
+			name: "youtube shorts link",
+			link: "https://youtube.com/shorts/dQw4w9WgXcQ?feature=share",
+			expectedVideo: &Video{
+				Provider:  "youtube",
+				ID:        "dQw4w9WgXcQ",
+				URL:       "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+				Thumbnail: "https://img.youtube.com/vi/dQw4w9WgXcQ/0.jpg",
+				EmbedURL:  "https://www.youtube.com/embed/dQw4w9WgXcQ",
+			},
+		},
+		{
+			name: "vimeo link",
+			link: "https://vimeo.com/channels/staffpicks/76979871",
+			expectedVideo: &Video{
+				Provider:  "vimeo",
+				ID:        "76979871",
+				URL:       "https://vimeo.com/76979871",
+				Thumbnail: "https://vumbnail.com/76979871.jpg",
+				EmbedURL:  "https://player.vimeo.com/video/76979871",
+			},
+		},
+		{
+			name: "dailymotion link",
+			link: "https://www.dailymotion.com/video/x7tgad0_title",
+			expectedVideo: &Video{
+				Provider:  "dailymotion",
+				ID:        "x7tgad0",
+				URL:       "https://www.dailymotion.com/video/x7tgad0",
+				Thumbnail: "https://www.dailymotion.com/thumbnail/video/x7tgad0",
+				EmbedURL:  "https://www.dailymotion.com/embed/video/x7tgad0",
+			},
+		},
+		{
+			name: "dailymotion short link",
+			link: "https://dai.ly/x7tgad0",
+			expectedVideo: &Video{
+				Provider:  "dailymotion",
+				ID:        "x7tgad0",
+				URL:       "https://www.dailymotion.com/video/x7tgad0",
+				Thumbnail: "https://www.dailymotion.com/thumbnail/video/x7tgad0",
+				EmbedURL:  "https://www.dailymotion.com/embed/video/x7tgad0",
+			},
+		},
+		{
+			name:        "youtube link without video id",
+			link:        "https://www.youtube.com/watch",
+			expectedErr: true,
+		},
+		{
+			name:        "lookalike domain",
+			link:        "https://notyoutube.com/watch?v=dQw4w9WgXcQ",
+			expectedErr: true,
+		},
+		{
+			name:        "unsupported provider",
+			link:        "https://example.com/video/1",
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		video, err := resolver.Resolve(testCase.link)
+		if testCase.expectedErr {
+			assert.Error(t, err, testCase.name)
+			assert.Nil(t, video, testCase.name)
+		} else {
+			assert.NoError(t, err, testCase.name)
+			assert.Equal(t, testCase.expectedVideo, video, testCase.name)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package videoprovider
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+var vimeoIDRegexp = regexp.MustCompile(`^[0-9]+$`)
+
+// Vimeo supports vimeo.com/{id}, vimeo.com/channels/{channel}/{id} and player.vimeo.com/video/{id} links
+type Vimeo struct{}
+
+func (p *Vimeo) Name() string {
+	return "vimeo"
+}
+
+func (p *Vimeo) Match(u *url.URL) (string, bool) {
+	if !hostIs(u.Host, "vimeo.com") {
+		return "", false
+	}
+
+	// the video id is the last numeric segment of the path
+	segments := pathSegments(u)
+	for i := len(segments) - 1; i >= 0; i-- {
+		if vimeoIDRegexp.MatchString(segments[i]) {
+			return segments[i], true
+		}
+	}
+	return "", false
+}
+
+func (p *Vimeo) WatchURL(videoID string) string {
+	return fmt.Sprintf("https://vimeo.com/%s", videoID)
+}
+
+func (p *Vimeo) ThumbnailURL(videoID string) string {
+	return fmt.Sprintf("https://vumbnail.com/%s.jpg", videoID)
+}
+
+func (p *Vimeo) EmbedURL(videoID string) string {
+	return fmt.Sprintf("https://player.vimeo.com/video/%s", videoID)
+}
+
+func (p *Vimeo) OEmbedEndpoint() string {
+	return "https://vimeo.com/api/oembed.json"
+}
@@ -0,0 +1,56 @@
+package videoprovider
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+var youtubeIDRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{6,}$`)
+
+// YouTube supports youtube.com/watch?v=, youtube.com/shorts/, youtube.com/embed/ and youtu.be/ links
+type YouTube struct{}
+
+func (p *YouTube) Name() string {
+	return "youtube"
+}
+
+func (p *YouTube) Match(u *url.URL) (string, bool) {
+	var videoID string
+	segments := pathSegments(u)
+
+	switch {
+	case hostIs(u.Host, "youtu.be"):
+		if len(segments) > 0 {
+			videoID = segments[0]
+		}
+	case hostIs(u.Host, "youtube.com", "youtube-nocookie.com"):
+		switch {
+		case len(segments) == 1 && segments[0] == "watch":
+			videoID = u.Query().Get("v")
+		case len(segments) >= 2 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live" || segments[0] == "v"):
+			videoID = segments[1]
+		}
+	}
+
+	if !youtubeIDRegexp.MatchString(videoID) {
+		return "", false
+	}
+	return videoID, true
+}
+
+func (p *YouTube) WatchURL(videoID string) string {
+	return fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
+}
+
+func (p *YouTube) ThumbnailURL(videoID string) string {
+	return fmt.Sprintf("https://img.youtube.com/vi/%s/0.jpg", videoID)
+}
+
+func (p *YouTube) EmbedURL(videoID string) string {
+	return fmt.Sprintf("https://www.youtube.com/embed/%s", videoID)
+}
+
+func (p *YouTube) OEmbedEndpoint() string {
+	return "https://www.youtube.com/oembed"
+}
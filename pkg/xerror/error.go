@@ -8,43 +8,128 @@ import (
 type Code int
 
 const (
-	NoError         = Code(0)
-	Unknown         = Code(1)
-	InvalidArgument = Code(2)
-	Internal        = Code(3)
-	UnAuthorized    = Code(4)
+	NoError          = Code(0)
+	Unknown          = Code(1)
+	InvalidArgument  = Code(2)
+	Internal         = Code(3)
+	UnAuthorized     = Code(4)
+	NotFound         = Code(5)
+	AlreadyExists    = Code(6)
+	PermissionDenied = Code(7)
+	Conflict         = Code(8)
+	RateLimited      = Code(9)
+	Unavailable      = Code(10)
+	MethodNotAllowed = Code(11)
 )
 
 var mCodeAndHttpStatus = map[Code]int{
-	Unknown:         http.StatusBadRequest,
-	InvalidArgument: http.StatusBadRequest,
-	Internal:        http.StatusInternalServerError,
-	UnAuthorized:    http.StatusUnauthorized,
+	Unknown:          http.StatusBadRequest,
+	InvalidArgument:  http.StatusBadRequest,
+	Internal:         http.StatusInternalServerError,
+	UnAuthorized:     http.StatusUnauthorized,
+	NotFound:         http.StatusNotFound,
+	AlreadyExists:    http.StatusConflict,
+	PermissionDenied: http.StatusForbidden,
+	Conflict:         http.StatusConflict,
+	RateLimited:      http.StatusTooManyRequests,
+	Unavailable:      http.StatusServiceUnavailable,
+	MethodNotAllowed: http.StatusMethodNotAllowed,
 }
 
+// mCodeAndName are the machine-readable names of the codes sent to clients
+var mCodeAndName = map[Code]string{
+	NoError:          "ok",
+	Unknown:          "unknown",
+	InvalidArgument:  "invalid_argument",
+	Internal:         "internal",
+	UnAuthorized:     "unauthorized",
+	NotFound:         "not_found",
+	AlreadyExists:    "already_exists",
+	PermissionDenied: "permission_denied",
+	Conflict:         "conflict",
+	RateLimited:      "rate_limited",
+	Unavailable:      "unavailable",
+	MethodNotAllowed: "method_not_allowed",
+}
+
+// mCodeAndMessage are sent to clients when the error has no message of its own
+var mCodeAndMessage = map[Code]string{
+	Unknown:          "unknown error",
+	InvalidArgument:  "invalid argument",
+	Internal:         "internal error",
+	UnAuthorized:     "unauthorized",
+	NotFound:         "not found",
+	AlreadyExists:    "already exists",
+	PermissionDenied: "permission denied",
+	Conflict:         "conflict",
+	RateLimited:      "too many requests",
+	Unavailable:      "service unavailable",
+	MethodNotAllowed: "method not allowed",
+}
+
+func (c Code) String() string {
+	if name, ok := mCodeAndName[c]; ok {
+		return name
+	}
+	return mCodeAndName[Unknown]
+}
+
+// ParseCode returns the code of a name returned by Code.String, Unknown when the name isn't known
+func ParseCode(name string) Code {
+	for code, n := range mCodeAndName {
+		if n == name {
+			return code
+		}
+	}
+	return Unknown
+}
+
+// FieldViolation describes why a field of the request is invalid
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// XError is returned to clients. Message and Details are exposed, Err is the cause
+// which is only logged as it may contain internal details, e.g. SQL errors.
 type XError struct {
 	Code    Code
 	Message string
+	Details []FieldViolation
 	Err     error
 }
 
 func (e XError) HttpStatus() int {
-	return mCodeAndHttpStatus[e.Code]
+	if status, ok := mCodeAndHttpStatus[e.Code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
 }
 
 func (e XError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
 	return e.Message
 }
 
+func (e XError) Unwrap() error {
+	return e.Err
+}
+
+// WithDetails returns a copy of the error with the field violations
+func (e XError) WithDetails(details ...FieldViolation) XError {
+	e.Details = append(append([]FieldViolation(nil), e.Details...), details...)
+	return e
+}
+
+// Error wraps err with the default message of the code, use ErrorM to send a specific message
 func Error(code Code, err error) XError {
-	xError := XError{
-		Code: code,
-		Err:  err,
-	}
-	if err != nil {
-		xError.Message = err.Error()
+	return XError{
+		Code:    code,
+		Message: mCodeAndMessage[code],
+		Err:     err,
 	}
-	return xError
 }
 
 func ErrorM(code Code, err error, msg string) XError {
@@ -0,0 +1,56 @@
+package xerror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	cause := fmt.Errorf("r.QueryContext: %w", sql.ErrConnDone)
+
+	err := Error(Internal, cause)
+
+	assert.Equal(t, "internal error", err.Message)
+	assert.Equal(t, http.StatusInternalServerError, err.HttpStatus())
+	assert.True(t, errors.Is(err, sql.ErrConnDone))
+}
+
+func TestCode(t *testing.T) {
+	testCases := []struct {
+		code   Code
+		name   string
+		status int
+	}{
+		{InvalidArgument, "invalid_argument", http.StatusBadRequest},
+		{UnAuthorized, "unauthorized", http.StatusUnauthorized},
+		{NotFound, "not_found", http.StatusNotFound},
+		{AlreadyExists, "already_exists", http.StatusConflict},
+		{PermissionDenied, "permission_denied", http.StatusForbidden},
+		{Conflict, "conflict", http.StatusConflict},
+		{RateLimited, "rate_limited", http.StatusTooManyRequests},
+		{Unavailable, "unavailable", http.StatusServiceUnavailable},
+		{MethodNotAllowed, "method_not_allowed", http.StatusMethodNotAllowed},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.name, testCase.code.String())
+		assert.Equal(t, testCase.code, ParseCode(testCase.name))
+		assert.Equal(t, testCase.status, Error(testCase.code, nil).HttpStatus())
+	}
+
+	assert.Equal(t, Unknown, ParseCode("unexpected"))
+}
+
+func TestXError_WithDetails(t *testing.T) {
+	err := ErrorM(InvalidArgument, nil, "invalid request")
+
+	detailed := err.WithDetails(FieldViolation{Field: "name", Description: "can't be null"})
+
+	assert.Empty(t, err.Details)
+	assert.Equal(t, []FieldViolation{{Field: "name", Description: "can't be null"}}, detailed.Details)
+}
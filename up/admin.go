@@ -0,0 +1,73 @@
+package up
+
+import "time"
+
+type ListUsersRequest struct {
+	Offset *int `json:"offset" validate:"min=0"`
+	Limit  *int `json:"limit" validate:"min=1,max=100"`
+}
+
+type ListUsersResponse struct {
+	Users  []*AdminUser `json:"users"`
+	Paging OffsetPaging `json:"paging"`
+}
+
+type ChangeUserRoleRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"required,oneof=user moderator admin"`
+}
+
+type ChangeUserRoleResponse struct {
+	AdminUser
+}
+
+type DisableUserRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+type DisableUserResponse struct{}
+
+type EnableUserRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+type EnableUserResponse struct{}
+
+type HideMovieRequest struct {
+	MovieID string `json:"movie_id" validate:"required"`
+}
+
+type HideMovieResponse struct{}
+
+type UnhideMovieRequest struct {
+	MovieID string `json:"movie_id" validate:"required"`
+}
+
+type UnhideMovieResponse struct{}
+
+type ListReportsRequest struct {
+	Offset *int `json:"offset" validate:"min=0"`
+	Limit  *int `json:"limit" validate:"min=1,max=100"`
+}
+
+type ListReportsResponse struct {
+	Reports []*Report    `json:"reports"`
+	Paging  OffsetPaging `json:"paging"`
+}
+
+type AdminUser struct {
+	ID         string     `json:"id"`
+	Username   string     `json:"username"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	DisabledAt *time.Time `json:"disabled_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type Report struct {
+	ID         string    `json:"id"`
+	MovieID    string    `json:"movie_id"`
+	ReportedBy string    `json:"reported_by"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
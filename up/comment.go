@@ -0,0 +1,69 @@
+package up
+
+import "time"
+
+type CreateCommentRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+	// ParentID is set when replying to a comment
+	ParentID string `json:"parent_id"`
+	Content  string `json:"content" validate:"required,max=2000"`
+}
+
+type CreateCommentResponse struct {
+	Comment
+}
+
+type EditCommentRequest struct {
+	ID      string `json:"id" path:"id" validate:"required"`
+	Content string `json:"content" validate:"required,max=2000"`
+}
+
+type EditCommentResponse struct {
+	Comment
+}
+
+type DeleteCommentRequest struct {
+	ID string `json:"id" path:"id" validate:"required"`
+}
+
+type DeleteCommentResponse struct{}
+
+type ListCommentsRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+	// Cursor is the next_cursor of the previous page, empty for the first page
+	Cursor string `json:"cursor"`
+	Limit  *int   `json:"limit" validate:"min=1,max=100"`
+}
+
+type ListCommentsResponse struct {
+	Comments []*Comment `json:"comments"`
+	// NextCursor is empty when there is no more comments
+	NextCursor string `json:"next_cursor"`
+}
+
+type ListRepliesRequest struct {
+	ID string `json:"id" path:"id" validate:"required"`
+	// Cursor is the replies_cursor of the comment or the next_cursor of the previous page
+	Cursor string `json:"cursor"`
+	Limit  *int   `json:"limit" validate:"min=1,max=100"`
+}
+
+type ListRepliesResponse struct {
+	Replies []*Comment `json:"replies"`
+	// NextCursor is empty when there is no more replies
+	NextCursor string `json:"next_cursor"`
+}
+
+type Comment struct {
+	ID        string     `json:"id"`
+	MovieID   string     `json:"movie_id"`
+	ParentID  string     `json:"parent_id,omitempty"`
+	Content   string     `json:"content"`
+	UserID    string     `json:"user_id"`
+	Author    string     `json:"author"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Replies   []*Comment `json:"replies,omitempty"`
+	// RepliesCursor is set when the comment has more replies than listed, it lists the next ones with ListReplies
+	RepliesCursor string `json:"replies_cursor,omitempty"`
+}
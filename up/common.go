@@ -1,10 +1,16 @@
 package up
 
+import "remi/pkg/xerror"
+
 type OffsetPaging struct {
 	Limit  int `json:"limit"`
 	Offset int `json:"offset"`
 }
 
+// ErrorResponse is the body of every error, Code is the name of an xerror.Code and
+// Details lists the invalid fields of the request
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error   string                  `json:"error"`
+	Code    string                  `json:"code"`
+	Details []xerror.FieldViolation `json:"details,omitempty"`
 }
@@ -0,0 +1,52 @@
+package up
+
+import "time"
+
+type FollowUserRequest struct {
+	UserID string `json:"user_id" path:"user_id" validate:"required"`
+}
+
+type FollowUserResponse struct{}
+
+type UnfollowUserRequest struct {
+	UserID string `json:"user_id" path:"user_id" validate:"required"`
+}
+
+type UnfollowUserResponse struct{}
+
+type ListFollowersRequest struct {
+	UserID string `json:"user_id" path:"user_id" validate:"required"`
+	// Cursor is the next_cursor of the previous page, empty for the first page
+	Cursor string `json:"cursor"`
+	Limit  *int   `json:"limit" validate:"min=1,max=100"`
+}
+
+// ListFollowersResponse lists the latest followers first
+type ListFollowersResponse struct {
+	Users []*FollowUser `json:"users"`
+	// NextCursor is empty when there is no more users
+	NextCursor string `json:"next_cursor"`
+}
+
+type ListFollowingRequest struct {
+	UserID string `json:"user_id" path:"user_id" validate:"required"`
+	// Cursor is the next_cursor of the previous page, empty for the first page
+	Cursor string `json:"cursor"`
+	Limit  *int   `json:"limit" validate:"min=1,max=100"`
+}
+
+// ListFollowingResponse lists the latest followed users first
+type ListFollowingResponse struct {
+	Users []*FollowUser `json:"users"`
+	// NextCursor is empty when there is no more users
+	NextCursor string `json:"next_cursor"`
+}
+
+// FollowUser is a follower or a followed user, FollowedAt is when the follow started
+type FollowUser struct {
+	ID         string    `json:"id"`
+	Username   string    `json:"username"`
+	Name       string    `json:"name"`
+	AvatarURL  string    `json:"avatar_url"`
+	FollowedAt time.Time `json:"followed_at"`
+}
@@ -1,86 +1,164 @@
 package up
 
-import (
-	"strings"
-	"time"
-
-	"remi/pkg/xerror"
-)
+import "time"
 
+// CreateMovieRequest name and description are prefilled from the video metadata when left blank.
+// Tags are normalized: lowercased, without a leading # and with their words joined by '-'.
 type CreateMovieRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Link        string `json:"link"`
-}
-
-func (r *CreateMovieRequest) Validate() error {
-	if strings.TrimSpace(r.Name) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "name can't be null")
-	}
-	if strings.TrimSpace(r.Link) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "link can't be null")
-	}
-	if strings.TrimSpace(r.Description) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "description can't be null")
-	}
-
-	return nil
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Link        string   `json:"link" validate:"required"`
+	Tags        []string `json:"tags" validate:"max=10"`
 }
 
 type CreateMovieResponse struct {
 	ID string `json:"id"`
 }
 
-type GetMovieByUserRequest struct {
-	ID string `json:"id"`
+type GetMovieRequest struct {
+	ID string `json:"id" path:"id" validate:"required"`
 }
 
-func (r *GetMovieByUserRequest) Validate() error {
-	if strings.TrimSpace(r.ID) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "id can't be null")
-	}
+type GetMovieResponse struct {
+	Movie
+}
 
-	return nil
+type GetMovieByUserRequest struct {
+	ID string `json:"id" validate:"required"`
 }
 
 type GetMovieByUserResponse struct {
 	Movie
 }
 
+// ListMoviesByUserRequest pages with Cursor when it's set, with Offset otherwise
 type ListMoviesByUserRequest struct {
-	Offset *int `json:"offset"`
-	Limit  *int `json:"limit"`
-}
-
-func (r *ListMoviesByUserRequest) Validate() error {
-	return nil
+	Offset *int `json:"offset" validate:"min=0"`
+	Limit  *int `json:"limit" validate:"min=1,max=100"`
+	// Cursor is the next_cursor of the previous page
+	Cursor string `json:"cursor"`
 }
 
 type ListMoviesByUserResponse struct {
 	Movies       []*Movie      `json:"movies"`
 	OffsetPaging *OffsetPaging `json:"paging"`
+	// NextCursor is empty when there is no more movies
+	NextCursor string `json:"next_cursor"`
 }
 
+// ListMoviesRequest pages with Cursor when it's set, with Offset otherwise. Only the movies with any of
+// the Tags are listed, or with all of them when TagMode is all.
 type ListMoviesRequest struct {
-	Offset *int `json:"offset"`
-	Limit  *int `json:"limit"`
+	Offset *int `json:"offset" validate:"min=0"`
+	Limit  *int `json:"limit" validate:"min=1,max=100"`
+	// Cursor is the next_cursor of the previous page
+	Cursor  string   `json:"cursor"`
+	Tags    []string `json:"tags" validate:"max=10"`
+	TagMode string   `json:"tag_mode" validate:"oneof=any all"`
 }
 
-func (r *ListMoviesRequest) Validate() error {
-	return nil
+type ListMoviesResponse struct {
+	Movies       []*Movie      `json:"movies"`
+	OffsetPaging *OffsetPaging `json:"paging"`
+	// NextCursor is empty when there is no more movies
+	NextCursor string `json:"next_cursor"`
 }
 
-type ListMoviesResponse struct {
+// ListMoviesByUsernameRequest lists the movies shared by the user with Username,
+// it pages with Cursor when it's set, with Offset otherwise
+type ListMoviesByUsernameRequest struct {
+	Username string `json:"username" path:"username" validate:"required"`
+	Offset   *int   `json:"offset" validate:"min=0"`
+	Limit    *int   `json:"limit" validate:"min=1,max=100"`
+	// Cursor is the next_cursor of the previous page
+	Cursor string `json:"cursor"`
+}
+
+type ListMoviesByUsernameResponse struct {
+	User         *PublicUser   `json:"user"`
 	Movies       []*Movie      `json:"movies"`
 	OffsetPaging *OffsetPaging `json:"paging"`
+	// NextCursor is empty when there is no more movies
+	NextCursor string `json:"next_cursor"`
+}
+
+// ListFeedRequest lists the movies shared by the users the caller follows,
+// it pages with Cursor when it's set, with Offset otherwise
+type ListFeedRequest struct {
+	Offset *int `json:"offset" validate:"min=0"`
+	Limit  *int `json:"limit" validate:"min=1,max=100"`
+	// Cursor is the next_cursor of the previous page
+	Cursor string `json:"cursor"`
 }
 
+type ListFeedResponse struct {
+	Movies       []*Movie      `json:"movies"`
+	OffsetPaging *OffsetPaging `json:"paging"`
+	// NextCursor is empty when there is no more movies
+	NextCursor string `json:"next_cursor"`
+}
+
+type SearchMoviesRequest struct {
+	Query  string `json:"query" validate:"required"`
+	Offset *int   `json:"offset" validate:"min=0"`
+	Limit  *int   `json:"limit" validate:"min=1,max=100"`
+}
+
+type SearchMoviesResponse struct {
+	Movies       []*MovieSearchResult `json:"movies"`
+	OffsetPaging *OffsetPaging        `json:"paging"`
+}
+
+// MovieSearchResult highlights matched words with <mark> tags, the rest of the highlights isn't escaped
+type MovieSearchResult struct {
+	Movie
+	Rank                 float64 `json:"rank"`
+	NameHighlight        string  `json:"name_highlight"`
+	DescriptionHighlight string  `json:"description_highlight"`
+}
+
+// UpdateMovieRequest only updates the fields which are set
+type UpdateMovieRequest struct {
+	ID          string  `json:"id" path:"id" validate:"required"`
+	Name        *string `json:"name" validate:"notblank"`
+	Description *string `json:"description"`
+}
+
+type UpdateMovieResponse struct {
+	Movie
+}
+
+type DeleteMovieRequest struct {
+	ID string `json:"id" path:"id" validate:"required"`
+}
+
+type DeleteMovieResponse struct{}
+
+type ReportMovieRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+	Reason  string `json:"reason" validate:"required,max=500"`
+}
+
+type ReportMovieResponse struct{}
+
+// Movie SharedByAvatar is the avatar URL of the sharer, empty when they have none,
+// SharedByUsername links to their page at /u/{username}. Watched is set when the caller viewed the movie.
 type Movie struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Link        string    `json:"link"`
-	Thumbnail   string    `json:"thumbnail"`
-	SharedBy    string    `json:"shared_by"`
-	SharedAt    time.Time `json:"shared_at"`
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Link             string    `json:"link"`
+	Thumbnail        string    `json:"thumbnail"`
+	Provider         string    `json:"provider"`
+	Author           string    `json:"author"`
+	Duration         int       `json:"duration"`
+	SharedBy         string    `json:"shared_by"`
+	SharedByAvatar   string    `json:"shared_by_avatar"`
+	SharedByUsername string    `json:"shared_by_username"`
+	SharedAt         time.Time `json:"shared_at"`
+	Tags             []string  `json:"tags"`
+	Likes            int       `json:"likes"`
+	Dislikes         int       `json:"dislikes"`
+	MyVote           string    `json:"my_vote"`
+	Watched          bool      `json:"watched"`
 }
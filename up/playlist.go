@@ -0,0 +1,107 @@
+package up
+
+import "time"
+
+const (
+	PlaylistPublic  = "public"
+	PlaylistPrivate = "private"
+)
+
+// MaxPlaylistItems is the maximum number of movies in a playlist
+const MaxPlaylistItems = 500
+
+// CreatePlaylistRequest playlists are private unless Visibility is public
+type CreatePlaylistRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+	Visibility  string `json:"visibility" validate:"oneof=public private"`
+}
+
+type CreatePlaylistResponse struct {
+	Playlist
+}
+
+// GetPlaylistRequest private playlists are only found by their owner
+type GetPlaylistRequest struct {
+	ID string `json:"id" path:"id" validate:"required"`
+}
+
+type GetPlaylistResponse struct {
+	Playlist
+}
+
+// ListPlaylistsRequest lists the public playlists and the playlists of the caller,
+// only the ones of UserID when it's set
+type ListPlaylistsRequest struct {
+	UserID string `json:"user_id"`
+	// Cursor is the next_cursor of the previous page, empty for the first page
+	Cursor string `json:"cursor"`
+	Limit  *int   `json:"limit" validate:"min=1,max=100"`
+}
+
+type ListPlaylistsResponse struct {
+	Playlists []*Playlist `json:"playlists"`
+	// NextCursor is empty when there is no more playlists
+	NextCursor string `json:"next_cursor"`
+}
+
+// UpdatePlaylistRequest only updates the fields which are set
+type UpdatePlaylistRequest struct {
+	ID          string  `json:"id" path:"id" validate:"required"`
+	Name        *string `json:"name" validate:"notblank,max=100"`
+	Description *string `json:"description" validate:"max=1000"`
+	Visibility  *string `json:"visibility" validate:"oneof=public private"`
+}
+
+type UpdatePlaylistResponse struct {
+	Playlist
+}
+
+type DeletePlaylistRequest struct {
+	ID string `json:"id" path:"id" validate:"required"`
+}
+
+type DeletePlaylistResponse struct{}
+
+// AddPlaylistItemRequest appends the movie to the end of the playlist
+type AddPlaylistItemRequest struct {
+	PlaylistID string `json:"playlist_id" path:"playlist_id" validate:"required"`
+	MovieID    string `json:"movie_id" validate:"required"`
+}
+
+type AddPlaylistItemResponse struct {
+	Playlist
+}
+
+type RemovePlaylistItemRequest struct {
+	PlaylistID string `json:"playlist_id" path:"playlist_id" validate:"required"`
+	MovieID    string `json:"movie_id" path:"movie_id" validate:"required"`
+}
+
+type RemovePlaylistItemResponse struct {
+	Playlist
+}
+
+// ReorderPlaylistItemsRequest MovieIDs lists every movie of the playlist in the new play order
+type ReorderPlaylistItemsRequest struct {
+	PlaylistID string   `json:"playlist_id" path:"playlist_id" validate:"required"`
+	MovieIDs   []string `json:"movie_ids" validate:"required,max=500"`
+}
+
+type ReorderPlaylistItemsResponse struct {
+	Playlist
+}
+
+// Playlist Items are the movies in play order, they're only returned for a single playlist
+type Playlist struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Owner       string    `json:"owner"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Visibility  string    `json:"visibility"`
+	ItemCount   int       `json:"item_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Items       []*Movie  `json:"items,omitempty"`
+}
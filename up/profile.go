@@ -0,0 +1,69 @@
+package up
+
+import "time"
+
+// MaxAvatarSize is the maximum size of avatar images in bytes
+const MaxAvatarSize = 2 << 20
+
+type GetProfileRequest struct{}
+
+type GetProfileResponse struct {
+	Profile
+}
+
+// UpdateProfileRequest only updates the fields which are set
+type UpdateProfileRequest struct {
+	Name *string `json:"name" validate:"notblank"`
+}
+
+type UpdateProfileResponse struct {
+	Profile
+}
+
+// ChangePasswordRequest revokes every refresh token of the account and the access token of the caller
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+// ChangePasswordResponse contains a new pair of tokens to stay logged in
+type ChangePasswordResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// UploadAvatarRequest Image is a PNG, JPEG, GIF or WebP image of at most MaxAvatarSize bytes,
+// base64 encoded in JSON
+type UploadAvatarRequest struct {
+	Image []byte `json:"image" validate:"required,max=2097152"`
+}
+
+type UploadAvatarResponse struct {
+	Profile
+}
+
+type DeleteAvatarRequest struct{}
+
+type DeleteAvatarResponse struct {
+	Profile
+}
+
+// Profile is the account of the caller, AvatarURL is empty when no avatar was uploaded
+type Profile struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	AvatarURL string    `json:"avatar_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PublicUser is what everyone sees of a user, Shares is the number of movies they shared
+type PublicUser struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	AvatarURL string    `json:"avatar_url"`
+	Shares    int       `json:"shares"`
+	CreatedAt time.Time `json:"created_at"`
+}
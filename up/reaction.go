@@ -0,0 +1,38 @@
+package up
+
+const (
+	VoteLike    = "like"
+	VoteDislike = "dislike"
+)
+
+type LikeMovieRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+}
+
+type LikeMovieResponse struct {
+	MovieReactions
+}
+
+type DislikeMovieRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+}
+
+type DislikeMovieResponse struct {
+	MovieReactions
+}
+
+type UnvoteMovieRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+}
+
+type UnvoteMovieResponse struct {
+	MovieReactions
+}
+
+// MovieReactions is the vote summary of a movie, MyVote is empty when the caller hasn't voted
+type MovieReactions struct {
+	MovieID  string `json:"movie_id"`
+	Likes    int    `json:"likes"`
+	Dislikes int    `json:"dislikes"`
+	MyVote   string `json:"my_vote"`
+}
@@ -4,14 +4,86 @@ import (
 	"context"
 )
 
+// Requests are validated by the router before the service is called, with their validate tags
+// (see pkg/validator) and then with Validate when they implement it
+
 type UserService interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	Refresh(context.Context, *RefreshRequest) (*RefreshResponse, error)
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	GetProfile(context.Context, *GetProfileRequest) (*GetProfileResponse, error)
+	UpdateProfile(context.Context, *UpdateProfileRequest) (*UpdateProfileResponse, error)
+	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	UploadAvatar(context.Context, *UploadAvatarRequest) (*UploadAvatarResponse, error)
+	DeleteAvatar(context.Context, *DeleteAvatarRequest) (*DeleteAvatarResponse, error)
 }
 
 type MovieService interface {
 	Create(context.Context, *CreateMovieRequest) (*CreateMovieResponse, error)
+	GetMovie(context.Context, *GetMovieRequest) (*GetMovieResponse, error)
 	GetMovieByUser(context.Context, *GetMovieByUserRequest) (*GetMovieByUserResponse, error)
 	ListMoviesByUser(context.Context, *ListMoviesByUserRequest) (*ListMoviesByUserResponse, error)
+	ListMoviesByUsername(context.Context, *ListMoviesByUsernameRequest) (*ListMoviesByUsernameResponse, error)
 	ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error)
+	ListFeed(context.Context, *ListFeedRequest) (*ListFeedResponse, error)
+	SearchMovies(context.Context, *SearchMoviesRequest) (*SearchMoviesResponse, error)
+	UpdateMovie(context.Context, *UpdateMovieRequest) (*UpdateMovieResponse, error)
+	DeleteMovie(context.Context, *DeleteMovieRequest) (*DeleteMovieResponse, error)
+	ReportMovie(context.Context, *ReportMovieRequest) (*ReportMovieResponse, error)
+}
+
+type ReactionService interface {
+	LikeMovie(context.Context, *LikeMovieRequest) (*LikeMovieResponse, error)
+	DislikeMovie(context.Context, *DislikeMovieRequest) (*DislikeMovieResponse, error)
+	UnvoteMovie(context.Context, *UnvoteMovieRequest) (*UnvoteMovieResponse, error)
+}
+
+type CommentService interface {
+	CreateComment(context.Context, *CreateCommentRequest) (*CreateCommentResponse, error)
+	EditComment(context.Context, *EditCommentRequest) (*EditCommentResponse, error)
+	DeleteComment(context.Context, *DeleteCommentRequest) (*DeleteCommentResponse, error)
+	ListComments(context.Context, *ListCommentsRequest) (*ListCommentsResponse, error)
+	ListReplies(context.Context, *ListRepliesRequest) (*ListRepliesResponse, error)
+}
+
+type FollowService interface {
+	FollowUser(context.Context, *FollowUserRequest) (*FollowUserResponse, error)
+	UnfollowUser(context.Context, *UnfollowUserRequest) (*UnfollowUserResponse, error)
+	ListFollowers(context.Context, *ListFollowersRequest) (*ListFollowersResponse, error)
+	ListFollowing(context.Context, *ListFollowingRequest) (*ListFollowingResponse, error)
+}
+
+type WatchService interface {
+	AddWatchLater(context.Context, *AddWatchLaterRequest) (*AddWatchLaterResponse, error)
+	RemoveWatchLater(context.Context, *RemoveWatchLaterRequest) (*RemoveWatchLaterResponse, error)
+	ListWatchLater(context.Context, *ListWatchLaterRequest) (*ListWatchLaterResponse, error)
+	RecordWatch(context.Context, *RecordWatchRequest) (*RecordWatchResponse, error)
+	RemoveWatchHistory(context.Context, *RemoveWatchHistoryRequest) (*RemoveWatchHistoryResponse, error)
+	ListWatchHistory(context.Context, *ListWatchHistoryRequest) (*ListWatchHistoryResponse, error)
+}
+
+type TagService interface {
+	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
+}
+
+type PlaylistService interface {
+	CreatePlaylist(context.Context, *CreatePlaylistRequest) (*CreatePlaylistResponse, error)
+	GetPlaylist(context.Context, *GetPlaylistRequest) (*GetPlaylistResponse, error)
+	ListPlaylists(context.Context, *ListPlaylistsRequest) (*ListPlaylistsResponse, error)
+	UpdatePlaylist(context.Context, *UpdatePlaylistRequest) (*UpdatePlaylistResponse, error)
+	DeletePlaylist(context.Context, *DeletePlaylistRequest) (*DeletePlaylistResponse, error)
+	AddPlaylistItem(context.Context, *AddPlaylistItemRequest) (*AddPlaylistItemResponse, error)
+	RemovePlaylistItem(context.Context, *RemovePlaylistItemRequest) (*RemovePlaylistItemResponse, error)
+	ReorderPlaylistItems(context.Context, *ReorderPlaylistItemsRequest) (*ReorderPlaylistItemsResponse, error)
+}
+
+type AdminService interface {
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	ChangeUserRole(context.Context, *ChangeUserRoleRequest) (*ChangeUserRoleResponse, error)
+	DisableUser(context.Context, *DisableUserRequest) (*DisableUserResponse, error)
+	EnableUser(context.Context, *EnableUserRequest) (*EnableUserResponse, error)
+	HideMovie(context.Context, *HideMovieRequest) (*HideMovieResponse, error)
+	UnhideMovie(context.Context, *UnhideMovieRequest) (*UnhideMovieResponse, error)
+	ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error)
 }
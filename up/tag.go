@@ -0,0 +1,21 @@
+package up
+
+const (
+	TagModeAny = "any"
+	TagModeAll = "all"
+)
+
+type ListTagsRequest struct {
+	Limit *int `json:"limit" validate:"min=1,max=200"`
+}
+
+// ListTagsResponse lists the most used tags first
+type ListTagsResponse struct {
+	Tags []*TagCount `json:"tags"`
+}
+
+// TagCount Count is the number of movies with the tag
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
@@ -1,51 +1,40 @@
 package up
 
-import (
-	"strings"
-
-	"remi/pkg/xerror"
-)
-
 type RegisterRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Name     string `json:"name"`
-}
-
-func (r *RegisterRequest) Validate() error {
-	if strings.TrimSpace(r.Username) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "username can't be null")
-	}
-	if strings.TrimSpace(r.Password) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "password can't be null")
-	}
-	if strings.TrimSpace(r.Name) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "name can't be null")
-	}
-
-	return nil
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	Name     string `json:"name" validate:"required"`
 }
 
 type RegisterResponse struct{}
 
 type LoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
-func (r *LoginRequest) Validate() error {
-	if strings.TrimSpace(r.Username) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "username can't be null")
-	}
-	if strings.TrimSpace(r.Password) == "" {
-		return xerror.ErrorM(xerror.InvalidArgument, nil, "password can't be null")
-	}
-	return nil
+type LoginResponse struct {
+	ID           string `json:"id"`
+	Username     string `json:"username"`
+	Name         string `json:"name"`
+	Role         string `json:"role"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
-type LoginResponse struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	Name     string `json:"name"`
-	Token    string `json:"token"`
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
+
+// RefreshResponse contains a new pair of tokens, the refresh token of the request can't be used anymore
+type RefreshResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LogoutRequest revokes the access token of the caller and the refresh token when it's set
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutResponse struct{}
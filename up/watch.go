@@ -0,0 +1,64 @@
+package up
+
+import "time"
+
+type AddWatchLaterRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+}
+
+type AddWatchLaterResponse struct{}
+
+type RemoveWatchLaterRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+}
+
+type RemoveWatchLaterResponse struct{}
+
+type ListWatchLaterRequest struct {
+	// Cursor is the next_cursor of the previous page, empty for the first page
+	Cursor string `json:"cursor"`
+	Limit  *int   `json:"limit" validate:"min=1,max=100"`
+}
+
+// ListWatchLaterResponse lists the latest saved movies first, deleted and hidden movies are left out
+type ListWatchLaterResponse struct {
+	Items []*WatchLaterItem `json:"items"`
+	// NextCursor is empty when there is no more items
+	NextCursor string `json:"next_cursor"`
+}
+
+type WatchLaterItem struct {
+	Movie   *Movie    `json:"movie"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// RecordWatchRequest records that the caller viewed the movie, movie pages record it by themselves
+type RecordWatchRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+}
+
+type RecordWatchResponse struct{}
+
+type RemoveWatchHistoryRequest struct {
+	MovieID string `json:"movie_id" path:"movie_id" validate:"required"`
+}
+
+type RemoveWatchHistoryResponse struct{}
+
+type ListWatchHistoryRequest struct {
+	// Cursor is the next_cursor of the previous page, empty for the first page
+	Cursor string `json:"cursor"`
+	Limit  *int   `json:"limit" validate:"min=1,max=100"`
+}
+
+// ListWatchHistoryResponse lists the last viewed movies first, deleted and hidden movies are left out
+type ListWatchHistoryResponse struct {
+	Items []*WatchHistoryItem `json:"items"`
+	// NextCursor is empty when there is no more items
+	NextCursor string `json:"next_cursor"`
+}
+
+type WatchHistoryItem struct {
+	Movie     *Movie    `json:"movie"`
+	WatchedAt time.Time `json:"watched_at"`
+}